@@ -0,0 +1,172 @@
+// Package catalog provides a catalog of localized error messages loaded from
+// message files, e.g. those embedded with embed.FS, for use in localizing
+// errors with errdetails.SetCatalog.
+//
+// Each message file holds the messages of a single locale, and is named by its
+// locale, e.g. "locales/fr-CA.json" for Canadian French:
+//
+//	{
+//	  "wallet.empty": "Votre portefeuille {currency} est vide."
+//	}
+//
+// Placeholders in curly braces are interpolated from arguments given when the
+// message is rendered.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Unmarshaler decodes the content of a message file into a map of message
+// templates by message ID, e.g. json.Unmarshal or toml.Unmarshal.
+type Unmarshaler func(data []byte, v interface{}) error
+
+// Option configures how a Catalog is loaded.
+type Option func(*options)
+
+type options struct {
+	defaultLocale string
+	formats       map[string]Unmarshaler
+}
+
+// WithDefault sets the default locale of the Catalog, used when none of the
+// locales preferred by a client are available.
+func WithDefault(locale string) Option {
+	return func(o *options) {
+		o.defaultLocale = locale
+	}
+}
+
+// WithFormat enables loading message files having a file extension, e.g.
+// WithFormat(".toml", toml.Unmarshal). Files in JSON format are always loaded.
+func WithFormat(ext string, fn Unmarshaler) Option {
+	return func(o *options) {
+		o.formats[ext] = fn
+	}
+}
+
+// Catalog holds message templates for each of its locales.
+type Catalog struct {
+	defaultLocale string
+	locales       []string
+	messages      map[string]map[string]string
+}
+
+// Load reads every message file in a directory of a file system into a
+// Catalog.
+//
+// Unless set by WithDefault, the default locale is the first locale loaded in
+// lexical order.
+func Load(fsys fs.FS, dir string, opts ...Option) (*Catalog, error) {
+	o := &options{
+		formats: map[string]Unmarshaler{
+			".json": json.Unmarshal,
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{defaultLocale: o.defaultLocale}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		unmarshal, ok := o.formats[ext]
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		messages := map[string]string{}
+		if err := unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("failed to load messages from %s: %w", entry.Name(), err)
+		}
+
+		c.Add(strings.TrimSuffix(entry.Name(), ext), messages)
+	}
+
+	return c, nil
+}
+
+// Add adds message templates by message ID to a locale of the Catalog,
+// replacing any existing message having the same ID.
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	if c.messages == nil {
+		c.messages = map[string]map[string]string{}
+	}
+	if _, ok := c.messages[locale]; !ok {
+		c.messages[locale] = map[string]string{}
+		c.locales = append(c.locales, locale)
+		sort.Strings(c.locales)
+	}
+
+	for id, msg := range messages {
+		c.messages[locale][id] = msg
+	}
+}
+
+// Locales lists the locales of the Catalog, the default locale first.
+func (c *Catalog) Locales() []string {
+	if c.defaultLocale == "" {
+		return c.locales
+	}
+
+	locales := []string{c.defaultLocale}
+	for _, locale := range c.locales {
+		if locale != c.defaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// Message renders the message identified by id in a locale, interpolating
+// placeholders from args. Placeholders not having an argument are left as-is.
+func (c *Catalog) Message(locale, id string, args map[string]string) (string, bool) {
+	tmpl, ok := c.messages[locale][id]
+	if !ok {
+		return "", false
+	}
+
+	return interpolate(tmpl, args), true
+}
+
+// interpolate replaces {placeholder} in a template with values from args.
+func interpolate(tmpl string, args map[string]string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(tmpl, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(tmpl[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(tmpl[:start])
+		if v, ok := args[tmpl[start+1:end]]; ok {
+			b.WriteString(v)
+		} else {
+			b.WriteString(tmpl[start : end+1])
+		}
+		tmpl = tmpl[end+1:]
+	}
+	b.WriteString(tmpl)
+
+	return b.String()
+}
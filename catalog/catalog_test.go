@@ -0,0 +1,73 @@
+package catalog_test
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/ClaudiaJ/errdetails/catalog"
+)
+
+//go:embed testdata/locales
+var locales embed.FS
+
+var _ errdetails.Catalog = (*catalog.Catalog)(nil)
+
+// unmarshalLines decodes "id=message" lines.
+func unmarshalLines(data []byte, v interface{}) error {
+	messages, ok := v.(*map[string]string)
+	if !ok {
+		return errors.New("unexpected messages type")
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if idx := strings.IndexByte(scanner.Text(), '='); idx > 0 {
+			(*messages)[scanner.Text()[:idx]] = scanner.Text()[idx+1:]
+		}
+	}
+	return scanner.Err()
+}
+
+func TestLoad(t *testing.T) {
+	c, err := catalog.Load(locales, "testdata/locales", catalog.WithDefault("en"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(c.Locales(), ","), "en,fr,fr-CA"; got != want {
+		t.Errorf("unexpected locales; got %s, want %s", got, want)
+	}
+
+	msg, ok := c.Message("fr", "wallet.empty", map[string]string{"currency": "Redlines"})
+	if !ok {
+		t.Fatal("expected message wallet.empty in locale fr")
+	}
+	if want := "Votre portefeuille Redlines est vide."; msg != want {
+		t.Errorf("unexpected message; got %q, want %q", msg, want)
+	}
+
+	if _, ok := c.Message("fr-CA", "maintenance", nil); ok {
+		t.Error("expected no message maintenance in locale fr-CA")
+	}
+}
+
+func TestLoadWithFormat(t *testing.T) {
+	c, err := catalog.Load(locales, "testdata/locales", catalog.WithFormat(".txt", unmarshalLines))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(c.Locales(), ","), "en,es-MX,fr,fr-CA"; got != want {
+		t.Errorf("unexpected locales; got %s, want %s", got, want)
+	}
+
+	msg, _ := c.Message("es-MX", "wallet.empty", nil)
+	if want := "Tu billetera de {currency} está vacía."; msg != want {
+		t.Errorf("unexpected message; got %q, want %q", msg, want)
+	}
+}
@@ -0,0 +1,45 @@
+package catalog_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/ClaudiaJ/errdetails/catalog"
+	detailspb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func ExampleLoad() {
+	// locales are embedded from message files, e.g.
+	//
+	//	//go:embed testdata/locales
+	//	var locales embed.FS
+	c, err := catalog.Load(locales, "testdata/locales", catalog.WithDefault("en"))
+	if err != nil {
+		panic(err)
+	}
+	errdetails.SetCatalog(c)
+	defer errdetails.SetCatalog(nil)
+
+	err = errdetails.New(codes.ResourceExhausted, "wallet is empty",
+		errdetails.Cause(&detailspb.ErrorInfo{
+			Reason:   "WALLET_EMPTY",
+			Domain:   "wallet.platform.test",
+			Metadata: map[string]string{"currency": "Redlines"},
+		}),
+		// rendered once the client's locale is known
+		errdetails.Localized("wallet.empty", nil),
+	)
+
+	for _, locale := range []string{"fr-CA", "fr-BE", "de"} {
+		var locErr errdetails.LocalizedError
+		if errors.As(errdetails.Localize(err, locale), &locErr) {
+			fmt.Printf("[%s]: %s\n", locErr.GetLocale(), locErr.GetMessage())
+		}
+	}
+	//output:
+	// [fr-CA]: Votre portefeuille Redlines est vide, mon ami.
+	// [fr]: Votre portefeuille Redlines est vide.
+	// [en]: Your Redlines wallet is empty.
+}
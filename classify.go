@@ -0,0 +1,98 @@
+package errdetails
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Classifier classifies an error not having a Status Code, e.g. one returned
+// by a library, by wrapping it with a Status Code and any other details.
+// Returning nil leaves the error to the next Classifier.
+type Classifier interface {
+	Classify(error) error
+}
+
+// ClassifierFunc type is an adapter to allow the use of ordinary functions as Classifier.
+type ClassifierFunc func(error) error
+
+// Classify calls fn(err).
+func (fn ClassifierFunc) Classify(err error) error {
+	return fn(err)
+}
+
+// Match classifies errors matching target with errors.Is, wrapping them with
+// the Status Code and any other details, e.g.
+//
+//	errdetails.RegisterClassifier(errdetails.Match(sql.ErrNoRows, codes.NotFound))
+func Match(target error, code codes.Code, details ...Details) Classifier {
+	return ClassifierFunc(func(err error) error {
+		if !errors.Is(err, target) {
+			return nil
+		}
+		return WithDetails(err, append([]Details{Code(code)}, details...)...)
+	})
+}
+
+// builtinClassifiers classify errors of the standard library.
+var builtinClassifiers = []Classifier{
+	Match(context.DeadlineExceeded, codes.DeadlineExceeded),
+	Match(context.Canceled, codes.Canceled),
+	Match(fs.ErrNotExist, codes.NotFound),
+	Match(fs.ErrExist, codes.AlreadyExists),
+	Match(fs.ErrPermission, codes.PermissionDenied),
+	ClassifierFunc(func(err error) error {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return WithDetails(err, Code(codes.DeadlineExceeded))
+		}
+		return nil
+	}),
+}
+
+type classifierChain struct {
+	mu          sync.RWMutex
+	classifiers []Classifier
+}
+
+var (
+	// classifiers are consulted before classifying errors as Unknown.
+	classifiers = &classifierChain{}
+)
+
+// RegisterClassifier registers a Classifier consulted by Classify, in order of
+// registration, before the built-in Classifiers.
+func RegisterClassifier(c Classifier) {
+	classifiers.mu.Lock()
+	defer classifiers.mu.Unlock()
+	classifiers.classifiers = append(classifiers.classifiers, c)
+}
+
+// Classify classifies an error not having a Status Code with the first of the
+// registered Classifiers having an opinion, or else the built-in Classifiers
+// of context errors, fs errors, and net.Error timeouts. Errors left
+// unclassified are returned as they are, to become Unknown.
+//
+// ToStatus, and everything built on it, classifies errors with Classify.
+func Classify(err error) error {
+	if err == nil || findStatus(err) != nil {
+		return err
+	}
+
+	classifiers.mu.RLock()
+	chain := make([]Classifier, 0, len(classifiers.classifiers)+len(builtinClassifiers))
+	chain = append(chain, classifiers.classifiers...)
+	classifiers.mu.RUnlock()
+
+	for _, c := range append(chain, builtinClassifiers...) {
+		if classified := c.Classify(err); classified != nil {
+			return classified
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,139 @@
+package errdetails
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestClassify(t *testing.T) {
+	testHandler(t)
+
+	for name, tc := range map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"deadline exceeded": {fmt.Errorf("get wallet: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		"canceled":          {context.Canceled, codes.Canceled},
+		"not exist":         {&fs.PathError{Op: "open", Path: "wallet.json", Err: fs.ErrNotExist}, codes.NotFound},
+		"exist":             {fs.ErrExist, codes.AlreadyExists},
+		"permission":        {fs.ErrPermission, codes.PermissionDenied},
+		"os deadline":       {os.ErrDeadlineExceeded, codes.DeadlineExceeded},
+		"net timeout":       {&net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}, codes.DeadlineExceeded},
+		"unclassified":      {errors.New("oops"), codes.Unknown},
+		"coded":             {New(codes.Internal, "oops"), codes.Internal},
+		"coded not exist":   {WithDetails(fs.ErrNotExist, Code(codes.Internal)), codes.Internal},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := ToStatus(tc.err)
+			require.Equal(t, tc.code, s.Code())
+			require.Equal(t, tc.err.Error(), s.Message())
+
+			err := Classify(tc.err)
+			require.True(t, errors.Is(err, tc.err), "expected classified error to wrap original error")
+		})
+	}
+
+	require.Nil(t, Classify(nil))
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	testHandler(t)
+	t.Cleanup(func() {
+		classifiers.mu.Lock()
+		defer classifiers.mu.Unlock()
+		classifiers.classifiers = nil
+	})
+
+	RegisterClassifier(Match(sql.ErrNoRows, codes.NotFound, Resource(&errdetails.ResourceInfo{
+		ResourceType: "wallet",
+	})))
+	RegisterClassifier(ClassifierFunc(func(err error) error {
+		if errors.Is(err, context.Canceled) {
+			return WithDetails(err, Code(codes.Aborted))
+		}
+		return nil
+	}))
+
+	err := Classify(fmt.Errorf("get wallet: %w", sql.ErrNoRows))
+	require.True(t, errors.Is(err, ErrNotFound), "expected error to be ErrNotFound")
+	require.True(t, errors.Is(err, sql.ErrNoRows), "expected error to be sql.ErrNoRows")
+
+	var resErr ResourceInfoError
+	require.True(t, errors.As(err, &resErr), "expected error to be ResourceInfoError")
+	require.Equal(t, "wallet", resErr.GetResourceType())
+
+	// registered classifiers are consulted before built-in classifiers
+	require.Equal(t, codes.Aborted, ToStatus(context.Canceled).Code())
+}
+
+func TestClassifyHandler(t *testing.T) {
+	testHandler(t)
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("read wallet: %w", fs.ErrNotExist)
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	err := CheckResponse(rr.Result())
+	require.True(t, errors.Is(err, ErrNotFound), "expected error to be ErrNotFound")
+}
+
+func TestClassifyHandlerOnce(t *testing.T) {
+	testHandler(t)
+	t.Cleanup(func() {
+		classifiers.mu.Lock()
+		defer classifiers.mu.Unlock()
+		classifiers.classifiers = nil
+	})
+
+	calls := 0
+	RegisterClassifier(ClassifierFunc(func(err error) error {
+		calls++
+		return nil
+	}))
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("unclassified")
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+	require.Equal(t, 1, calls, "expected the error to be classified once")
+}
+
+func TestClassifyServerInterceptor(t *testing.T) {
+	testHandler(t)
+
+	_, err := UnaryServerInterceptor(context.Background(), nil, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, context.DeadlineExceeded
+	})
+
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
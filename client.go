@@ -0,0 +1,140 @@
+package errdetails
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxErrorBodySize limits how much of an error response body is read.
+const maxErrorBodySize = 1 << 20
+
+// Transport is an http.RoundTripper rebuilding errors from responses having a
+// 4xx or 5xx status code with CheckResponse. Other responses, like redirects,
+// are returned as they are.
+//
+// Note that http.Client wraps errors returned by a RoundTripper in url.Error,
+// which still satisfies errors.Is and errors.As for the rebuilt error.
+type Transport struct {
+	// Base is the RoundTripper used to make requests.
+	// If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	res, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckResponse(res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CheckResponse returns nil for responses having a status code below 400,
+// otherwise it reads and closes the response body to rebuild the error from a
+// Status document the same as FromJSON, or from a binary Protobuf Status or
+// problem details document by the Content-Type of the response.
+//
+// Should the body not be a Status document, the error has a Status Code
+// derived from the HTTP status code of the response, and RetriableError
+// details if the response has a Retry-After header in seconds. Should any of
+// the details of a Status document fail to unmarshal, the error has the Status
+// Code and message of the Status document instead, and the unmarshal error is
+// reported to the ErrorHandler.
+func CheckResponse(res *http.Response) error {
+	if res.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	defer res.Body.Close()
+
+	code, msg := codeFromHTTPStatus(res.StatusCode), http.StatusText(res.StatusCode)
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	if err == nil {
+		mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+
+		s := &statuspb.Status{}
+		switch mediaType {
+		case problemContentType:
+			if json.Valid(body) {
+				return FromProblemJSON(bytes.NewReader(body))
+			}
+			err = errors.New("invalid problem details document")
+		case protobufContentType:
+			err = proto.Unmarshal(body, s)
+		default:
+			err = protojson.Unmarshal(body, s)
+		}
+		if err == nil && s.Code != int32(codes.OK) {
+			rebuilt, uerr := fromStatus(status.FromProto(s))
+			if uerr == nil {
+				return rebuilt
+			}
+
+			handler.Handle(uerr)
+			code, msg = codes.Code(s.Code), s.Message
+		}
+	}
+
+	err = New(code, msg)
+	if secs, perr := strconv.Atoi(res.Header.Get("Retry-After")); perr == nil && secs > 0 {
+		err = WithRetryDelay(err, time.Duration(secs)*time.Second)
+	}
+	return err
+}
+
+// codeFromHTTPStatus translates an HTTP status code to the Status Code best
+// describing it, the inverse of runtime.HTTPStatusFromCode.
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return codes.OutOfRange
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // Client Closed Request
+		return codes.Canceled
+	case http.StatusInternalServerError:
+		return codes.Internal
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+
+	return codes.Unknown
+}
@@ -0,0 +1,121 @@
+package errdetails
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestTransport(t *testing.T) {
+	testHandler(t)
+
+	srv := httptest.NewServer(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return New(codes.ResourceExhausted, "resend email rate limit exceeded",
+			RetryDelay(time.Minute),
+			QuotaFailure(&errdetails.QuotaFailure_Violation{
+				Subject:     "auth0|123456789",
+				Description: "Rate limit applied for Resend Email",
+			}),
+		)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+
+	res, err := client.Get(srv.URL)
+	require.Nil(t, res)
+	require.True(t, errors.Is(err, ErrResourceExhausted), "expected error to be ErrResourceExhausted")
+
+	var retErr RetriableError
+	require.True(t, errors.As(err, &retErr), "expected error to be RetriableError")
+	require.Equal(t, time.Minute, retErr.GetRetryDelay())
+}
+
+func TestCheckResponse(t *testing.T) {
+	testHandler(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusNoContent)
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		case "/json":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": "upstream unavailable"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for path, want := range map[string]error{
+		"/ok":           nil,
+		"/redirect":     nil,
+		"/not-modified": nil,
+		"/json":         ErrUnavailable,
+		"/missing":      ErrNotFound,
+	} {
+		res, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+
+		if err := CheckResponse(res); want == nil {
+			require.NoError(t, err)
+			res.Body.Close()
+		} else {
+			require.True(t, errors.Is(err, want), "expected %s error to be %v, got %v", path, want, err)
+		}
+	}
+}
+
+func TestCheckResponseUnknownDetail(t *testing.T) {
+	reported := recoverHandler(t)
+
+	b, err := proto.Marshal(&statuspb.Status{
+		Code:    int32(codes.Aborted),
+		Message: "conflict",
+		Details: []*anypb.Any{{TypeUrl: "type.googleapis.com/unknown.Detail"}},
+	})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	rr.Header().Set("Content-Type", protobufContentType)
+	rr.WriteHeader(http.StatusConflict)
+	rr.Write(b)
+
+	err = CheckResponse(rr.Result())
+	require.True(t, errors.Is(err, ErrAborted), "expected error to be ErrAborted")
+	require.Equal(t, "conflict", err.Error())
+	require.Len(t, *reported, 1)
+}
+
+func TestTransportRedirect(t *testing.T) {
+	testHandler(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+
+	res, err := client.Get(srv.URL + "/old")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+	require.Equal(t, "/new", res.Request.URL.Path)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+// catalog is a catalog of error reasons of a single domain.
+type catalog struct {
+	Package string   `yaml:"package"`
+	Domain  string   `yaml:"domain"`
+	Reasons []reason `yaml:"reasons"`
+}
+
+// reason describes an error reason.
+type reason struct {
+	Reason      string            `yaml:"reason"`
+	Code        string            `yaml:"code"`
+	Description string            `yaml:"description"`
+	Message     string            `yaml:"message"`
+	Metadata    []metadataKey     `yaml:"metadata"`
+	Help        []helpLink        `yaml:"help"`
+	Localized   map[string]string `yaml:"localized"`
+}
+
+// metadataKey describes a metadata key of an error reason, and the type of the
+// constructor parameter setting it.
+type metadataKey struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+type helpLink struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+}
+
+// formats are the Go expressions formatting a parameter of each type as string.
+var formats = map[string]string{
+	"string":  "%s",
+	"int":     "strconv.Itoa(%s)",
+	"int64":   "strconv.FormatInt(%s, 10)",
+	"uint":    "strconv.FormatUint(uint64(%s), 10)",
+	"uint64":  "strconv.FormatUint(%s, 10)",
+	"bool":    "strconv.FormatBool(%s)",
+	"float64": "strconv.FormatFloat(%s, 'g', -1, 64)",
+}
+
+func parseCatalog(data []byte) (*catalog, error) {
+	c := &catalog{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+
+	if c.Domain == "" {
+		return nil, errors.New("missing domain")
+	}
+
+	seen := map[string]bool{}
+	for idx := range c.Reasons {
+		r := &c.Reasons[idx]
+		if r.Reason == "" {
+			return nil, fmt.Errorf("reasons[%d]: missing reason", idx)
+		}
+		if seen[r.Reason] {
+			return nil, fmt.Errorf("reason %s: declared more than once", r.Reason)
+		}
+		seen[r.Reason] = true
+
+		code, ok := parseCode(r.Code)
+		if !ok {
+			return nil, fmt.Errorf("reason %s: unknown code %q", r.Reason, r.Code)
+		}
+		r.Code = code.String()
+
+		for idx := range r.Metadata {
+			key := &r.Metadata[idx]
+			if key.Name == "" {
+				return nil, fmt.Errorf("reason %s: metadata[%d]: missing name", r.Reason, idx)
+			}
+			if key.Type == "" {
+				key.Type = "string"
+			}
+			if _, ok := formats[key.Type]; !ok {
+				return nil, fmt.Errorf("reason %s: metadata %s: unsupported type %q", r.Reason, key.Name, key.Type)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// parseCode parses a Status Code by name, e.g. "NotFound" or "NOT_FOUND".
+func parseCode(name string) (codes.Code, bool) {
+	name = strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if strings.ToLower(code.String()) == name {
+			return code, true
+		}
+	}
+	return codes.Unknown, false
+}
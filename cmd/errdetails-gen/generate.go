@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var fileTemplate = template.Must(template.New("file").Funcs(template.FuncMap{"comment": comment}).Parse(`// Code generated by errdetails-gen. DO NOT EDIT.
+// source: {{ .Source }}
+
+package {{ .Package }}
+
+import (
+{{- if .Strconv }}
+	"strconv"
+{{ end }}
+	"github.com/ClaudiaJ/errdetails"
+{{- if .Help }}
+	pb "google.golang.org/genproto/googleapis/rpc/errdetails"
+{{- end }}
+	"google.golang.org/grpc/codes"
+)
+
+// Reasons of the {{ .Domain }} domain, for use as target of errors.Is().
+var (
+{{- range .Reasons }}
+	// {{ .Sentinel }} is the {{ .Reason }} reason.{{ with .Description }} {{ comment . }}{{ end }}
+	{{ .Sentinel }} = errdetails.Reason({{ printf "%q" $.Domain }}, {{ printf "%q" .Reason }}, codes.{{ .Code }})
+{{- end }}
+)
+{{ range .Reasons }}
+// {{ .Constructor }} creates an error having the {{ .Reason }} reason, enriched
+// with any other details.
+func {{ .Constructor }}({{ range .Params }}{{ .Name }} {{ .Type }}, {{ end }}details ...errdetails.Details) error {
+{{- if .Params }}
+	md := map[string]string{
+	{{- range .Params }}
+		{{ printf "%q" .Key }}: {{ .Format }},
+	{{- end }}
+	}
+{{ end }}
+	return errdetails.New(codes.{{ .Code }}, {{ .MessageExpr }}, append([]errdetails.Details{
+		{{ .Sentinel }}.WithMetadata({{ if .Params }}md{{ else }}nil{{ end }}),
+	{{- if .Help }}
+		errdetails.Help({{ range .Help }}&pb.Help_Link{
+			Url:         {{ printf "%q" .URL }},
+			Description: {{ printf "%q" .Description }},
+		}, {{ end }}),
+	{{- end }}
+	{{- if .Localized }}
+		errdetails.Localized({{ printf "%q" .ID }}, {{ if .Params }}md{{ else }}nil{{ end }}),
+	{{- end }}
+	}, details...)...)
+}
+{{ end }}
+{{- if .Locales }}
+// Messages holds the localized message templates of the reasons by locale,
+// identified by domain/reason, e.g. for adding to a catalog.Catalog.
+var Messages = map[string]map[string]string{
+{{- range .Locales }}
+	{{ printf "%q" .Locale }}: {
+	{{- range .Messages }}
+		{{ printf "%q" .ID }}: {{ printf "%q" .Message }},
+	{{- end }}
+	},
+{{- end }}
+}
+{{ end -}}
+`))
+
+type fileData struct {
+	Source  string
+	Package string
+	Domain  string
+	Strconv bool
+	Help    bool
+	Reasons []reasonData
+	Locales []localeData
+}
+
+type reasonData struct {
+	reason
+	ID          string
+	Sentinel    string
+	Constructor string
+	Params      []paramData
+	MessageExpr string
+}
+
+type paramData struct {
+	Key    string
+	Name   string
+	Type   string
+	Format string
+}
+
+type localeData struct {
+	Locale   string
+	Messages []localizedData
+}
+
+type localizedData struct {
+	ID      string
+	Message string
+}
+
+// generate generates the Go source of a catalog.
+func generate(c *catalog, source string) ([]byte, error) {
+	if c.Package == "" {
+		return nil, fmt.Errorf("missing package name")
+	}
+
+	data := fileData{
+		Source:  source,
+		Package: c.Package,
+		Domain:  c.Domain,
+	}
+
+	localized := map[string][]localizedData{}
+	for _, r := range c.Reasons {
+		name := camelCase(r.Reason, true)
+		rd := reasonData{
+			reason: r,
+			// message IDs are qualified by domain, such that reasons of
+			// different domains don't collide in a shared catalog
+			ID:          c.Domain + "/" + r.Reason,
+			Sentinel:    "Err" + name,
+			Constructor: "New" + name,
+		}
+
+		md := map[string]bool{}
+		for _, key := range r.Metadata {
+			param := paramData{
+				Key:  key.Name,
+				Name: paramName(key.Name),
+				Type: key.Type,
+			}
+			param.Format = fmt.Sprintf(formats[key.Type], param.Name)
+			data.Strconv = data.Strconv || key.Type != "string"
+
+			md[key.Name] = true
+			rd.Params = append(rd.Params, param)
+		}
+
+		expr, err := messageExpr(r.Message, md)
+		if err != nil {
+			return nil, fmt.Errorf("reason %s: %w", r.Reason, err)
+		}
+		rd.MessageExpr = expr
+
+		data.Help = data.Help || len(r.Help) > 0
+		for locale, msg := range r.Localized {
+			localized[locale] = append(localized[locale], localizedData{ID: rd.ID, Message: msg})
+		}
+
+		data.Reasons = append(data.Reasons, rd)
+	}
+
+	for locale, msgs := range localized {
+		data.Locales = append(data.Locales, localeData{Locale: locale, Messages: msgs})
+	}
+	sort.Slice(data.Locales, func(i, j int) bool {
+		return data.Locales[i].Locale < data.Locales[j].Locale
+	})
+
+	buf := new(bytes.Buffer)
+	if err := fileTemplate.Execute(buf, data); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// messageExpr translates a message template to a Go expression concatenating
+// its text with the metadata of its placeholders.
+func messageExpr(msg string, md map[string]bool) (string, error) {
+	var parts []string
+	for rest := msg; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		end := strings.IndexByte(rest[start+1:], '}') + start + 1
+		if start < 0 || end <= start {
+			parts = append(parts, strconv.Quote(rest))
+			break
+		}
+
+		key := rest[start+1 : end]
+		if !md[key] {
+			return "", fmt.Errorf("message placeholder {%s} is not a metadata key", key)
+		}
+
+		if start > 0 {
+			parts = append(parts, strconv.Quote(rest[:start]))
+		}
+		parts = append(parts, fmt.Sprintf("md[%q]", key))
+		rest = rest[end+1:]
+	}
+
+	if len(parts) == 0 {
+		return `""`, nil
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+// comment continues a line comment of the sentinels with text, commenting each
+// line of a multi-line description.
+func comment(text string) string {
+	var b strings.Builder
+	for idx, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if idx > 0 {
+			b.WriteString("\n\t//")
+		}
+		if line = strings.TrimRightFunc(line, unicode.IsSpace); line != "" {
+			if idx > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// camelCase converts a name like "WALLET_EMPTY" or "wallet-id" to "WalletEmpty"
+// or "walletID" in lower camel case.
+func camelCase(name string, upper bool) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for idx, word := range words {
+		word = strings.ToLower(word)
+		switch {
+		case idx == 0 && !upper:
+		case word == "id" || word == "url" || word == "uri" || word == "http":
+			word = strings.ToUpper(word)
+		default:
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// paramName converts a metadata key to a parameter name, avoiding keywords and
+// names used by the generated code.
+func paramName(key string) string {
+	name := camelCase(key, false)
+	if token.IsKeyword(name) || name == "md" || name == "details" || name == "" {
+		name += "Value"
+	}
+	return name
+}
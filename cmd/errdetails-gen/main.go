@@ -0,0 +1,83 @@
+// Command errdetails-gen generates Go sentinels and constructors of errdetails
+// errors from a YAML catalog of error reasons, e.g. from a go:generate
+// directive:
+//
+//	//go:generate go run github.com/ClaudiaJ/errdetails/cmd/errdetails-gen -in reasons.yaml -out reasons_gen.go
+//
+// A catalog declares the reasons of a single domain:
+//
+//	domain: wallet.platform.test
+//	reasons:
+//	  - reason: WALLET_EMPTY
+//	    code: ResourceExhausted
+//	    description: The wallet has no more of a product available.
+//	    message: wallet {wallet} has no more {product} available
+//	    metadata:
+//	      - name: wallet
+//	      - name: product
+//	      - name: balance
+//	        type: int
+//	    help:
+//	      - url: https://wallet.platform.test/docs/empty
+//	        description: Refilling a wallet
+//	    localized:
+//	      en: Your wallet has no more {product} available.
+//	      fr: Votre portefeuille n'a plus de {product} disponible.
+//
+// For each reason, an Err sentinel is generated with errdetails.Reason, along
+// with a New constructor taking typed parameters for each metadata key.
+// Message templates are interpolated from the metadata by placeholders in
+// curly braces.
+//
+// Localized messages are generated into a Messages map by locale, identified
+// by domain/reason, e.g. "wallet.platform.test/WALLET_EMPTY", for adding to a
+// catalog.Catalog.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "", "path of the YAML catalog of error reasons")
+	out := flag.String("out", "", "path of the generated Go file, or stdout if empty")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated Go file")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "errdetails-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg string) error {
+	if in == "" {
+		return fmt.Errorf("missing -in flag")
+	}
+
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+
+	c, err := parseCatalog(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	if pkg != "" {
+		c.Package = pkg
+	}
+
+	src, err := generate(c, in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "reasons_gen.go")
+	require.NoError(t, run("testdata/reasons.yaml", out, ""))
+
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "reasons_gen.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestParseCatalogErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"missing domain": `reasons: [{reason: A, code: NotFound}]`,
+		"unknown code":   `{domain: d, reasons: [{reason: A, code: Missing}]}`,
+		"duplicate":      `{domain: d, reasons: [{reason: A, code: NotFound}, {reason: A, code: NotFound}]}`,
+		"unknown type":   `{domain: d, reasons: [{reason: A, code: NotFound, metadata: [{name: a, type: complex128}]}]}`,
+	} {
+		_, err := parseCatalog([]byte(data))
+		require.Error(t, err, name)
+	}
+
+	c, err := parseCatalog([]byte(`{package: p, domain: d, reasons: [{reason: A, code: NotFound, message: "{missing}"}]}`))
+	require.NoError(t, err)
+	_, err = generate(c, "test.yaml")
+	require.Error(t, err)
+}
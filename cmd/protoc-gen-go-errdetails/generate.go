@@ -0,0 +1,145 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	errdetailsPackage = protogen.GoImportPath("github.com/ClaudiaJ/errdetails")
+	codesPackage      = protogen.GoImportPath("google.golang.org/grpc/codes")
+)
+
+// generateFile generates the errdetails errors of a proto file, if it has any
+// ErrorReason enums.
+func generateFile(gen *protogen.Plugin, f *protogen.File) *protogen.GeneratedFile {
+	enums := reasonEnums(f.Enums, f.Messages)
+	if len(enums) == 0 {
+		return nil
+	}
+
+	g := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+"_errdetails.pb.go", f.GoImportPath)
+	g.P("// Code generated by protoc-gen-go-errdetails. DO NOT EDIT.")
+	g.P("// source: ", f.Desc.Path())
+	g.P()
+	g.P("package ", f.GoPackageName)
+	g.P()
+
+	for _, enum := range enums {
+		generateEnum(g, f, enum)
+	}
+
+	return g
+}
+
+// reasonEnums finds the enums named ErrorReason, or having the reasons option,
+// including those nested in messages.
+func reasonEnums(enums []*protogen.Enum, msgs []*protogen.Message) []*protogen.Enum {
+	var found []*protogen.Enum
+	for _, enum := range enums {
+		if len(readExtensions(enum.Desc.Options())) > 0 || enum.Desc.Name() == "ErrorReason" {
+			found = append(found, enum)
+		}
+	}
+	for _, msg := range msgs {
+		found = append(found, reasonEnums(msg.Enums, msg.Messages)...)
+	}
+	return found
+}
+
+func generateEnum(g *protogen.GeneratedFile, f *protogen.File, enum *protogen.Enum) {
+	opts := readExtensions(enum.Desc.Options())
+
+	domain, ok := opts.string(domainField)
+	if !ok {
+		domain = string(f.Desc.Package())
+	}
+	defaultCode, _ := opts.code(defaultCodeField)
+
+	// only a top-level ErrorReason goes without a prefix, such that reasons of
+	// a nested ErrorReason don't collide with it
+	prefix := ""
+	if _, nested := enum.Desc.Parent().(protoreflect.MessageDescriptor); nested || enum.Desc.Name() != "ErrorReason" {
+		prefix = strings.ReplaceAll(enum.GoIdent.GoName, "_", "")
+	}
+
+	type reason struct {
+		*protogen.EnumValue
+		name    string
+		code    codes.Code
+		message string
+	}
+
+	var reasons []reason
+	for _, value := range enum.Values {
+		if value.Desc.Number() == 0 {
+			continue
+		}
+
+		r := reason{
+			EnumValue: value,
+			name:      prefix + camelCase(string(value.Desc.Name())),
+			code:      defaultCode,
+			message:   string(value.Desc.Name()),
+		}
+
+		opts := readExtensions(value.Desc.Options())
+		if code, ok := opts.code(codeField); ok {
+			r.code = code
+		}
+		if msg, ok := opts.string(messageField); ok {
+			r.message = msg
+		}
+		reasons = append(reasons, r)
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	reasonIdent := g.QualifiedGoIdent(errdetailsPackage.Ident("Reason"))
+	newIdent := g.QualifiedGoIdent(errdetailsPackage.Ident("New"))
+	detailsIdent := g.QualifiedGoIdent(errdetailsPackage.Ident("Details"))
+
+	g.P("// Reasons of the ", enum.GoIdent.GoName, " enum, for use as target of errors.Is().")
+	g.P("var (")
+	for _, r := range reasons {
+		g.P("// Err", r.name, " is the ", r.Desc.Name(), " reason of the ", domain, " domain.")
+		if comment := strings.TrimSpace(string(r.Comments.Leading)); comment != "" {
+			g.P("//")
+			for _, line := range strings.Split(comment, "\n") {
+				g.P("// ", strings.TrimSpace(line))
+			}
+		}
+		g.P("Err", r.name, " = ", reasonIdent, "(", strconv.Quote(domain), ", ",
+			strconv.Quote(string(r.Desc.Name())), ", ", g.QualifiedGoIdent(codesPackage.Ident(r.code.String())), ")")
+	}
+	g.P(")")
+	g.P()
+
+	for _, r := range reasons {
+		g.P("// New", r.name, " creates an error having the ", r.Desc.Name(), " reason,")
+		g.P("// enriched with any other details.")
+		g.P("func New", r.name, "(metadata map[string]string, details ...", detailsIdent, ") error {")
+		g.P("return ", newIdent, "(", g.QualifiedGoIdent(codesPackage.Ident(r.code.String())), ", ", strconv.Quote(r.message), ", append([]", detailsIdent, "{")
+		g.P("Err", r.name, ".WithMetadata(metadata),")
+		g.P("}, details...)...)")
+		g.P("}")
+		g.P()
+	}
+}
+
+// camelCase converts a name like "WALLET_EMPTY" to "WalletEmpty".
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(strings.ToLower(name), "_") {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+// Command protoc-gen-go-errdetails is a protoc plugin generating Go sentinels
+// and constructors of errdetails errors for each reason of the ErrorReason
+// enums of proto files, keeping proto contracts and Go error construction in
+// sync.
+//
+//	protoc -I . -I path/to/errdetails/proto --go_out=. --go-errdetails_out=. wallet/v1/errors.proto
+//
+// Enums named ErrorReason, or having the errdetails.reasons option, are
+// generated. Following the convention of ErrorInfo, the reason of each enum
+// value is its name, and the zero value is left out as unspecified.
+//
+// The domain and codes of reasons are set with the options declared in
+// proto/errdetails/options.proto, imported as "errdetails/options.proto" with
+// the proto directory of this module as an import path. Go code generated for
+// proto files importing the options imports their Go package,
+// github.com/ClaudiaJ/errdetails/proto/errdetails.
+//
+//	enum ErrorReason {
+//	  option (errdetails.reasons).domain = "wallet.platform.test";
+//
+//	  ERROR_REASON_UNSPECIFIED = 0;
+//	  WALLET_EMPTY = 1 [(errdetails.reason).code = RESOURCE_EXHAUSTED];
+//	}
+//
+// For each reason, an Err sentinel is generated with errdetails.Reason, along
+// with a New constructor. Reasons of enums named other than ErrorReason, or
+// nested in a message, are prefixed by the Go name of the enum.
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func main() {
+	protogen.Options{}.Run(run)
+}
+
+func run(gen *protogen.Plugin) error {
+	// only enums are generated, which proto3 optional fields don't affect
+	gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f)
+		}
+	}
+	return nil
+}
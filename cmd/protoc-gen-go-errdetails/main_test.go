@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	errdetailspb "github.com/ClaudiaJ/errdetails/proto/errdetails"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate(t *testing.T) {
+	enumOptions := &descriptorpb.EnumOptions{}
+	enumOptions.ProtoReflect().SetUnknown(appendOptions(nil, appendCode(
+		appendString(nil, domainField, "wallet.platform.test"),
+		defaultCodeField, 9, // FAILED_PRECONDITION
+	)))
+
+	emptyOptions := &descriptorpb.EnumValueOptions{}
+	emptyOptions.ProtoReflect().SetUnknown(appendOptions(
+		appendOptions(nil, appendCode(nil, codeField, 8)), // RESOURCE_EXHAUSTED
+		appendString(nil, messageField, "wallet has no more Redlines available"),
+	))
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"wallet/v1/errors.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("wallet/v1/errors.proto"),
+			Package: proto.String("wallet.v1"),
+			Syntax:  proto.String("proto3"),
+			Options: &descriptorpb.FileOptions{
+				GoPackage: proto.String("example.test/wallet/v1;walletv1"),
+			},
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name:    proto.String("ErrorReason"),
+				Options: enumOptions,
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("ERROR_REASON_UNSPECIFIED"), Number: proto.Int32(0)},
+					{Name: proto.String("WALLET_EMPTY"), Number: proto.Int32(1), Options: emptyOptions},
+					{Name: proto.String("WALLET_FROZEN"), Number: proto.Int32(2)},
+				},
+			}, {
+				// not an ErrorReason enum
+				Name: proto.String("Currency"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("CURRENCY_UNSPECIFIED"), Number: proto.Int32(0)},
+				},
+			}},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Transfer"),
+				EnumType: []*descriptorpb.EnumDescriptorProto{{
+					Name: proto.String("Failure"),
+					Options: func() *descriptorpb.EnumOptions {
+						opts := &descriptorpb.EnumOptions{}
+						opts.ProtoReflect().SetUnknown(appendOptions(nil, appendString(nil, domainField, "transfer.platform.test")))
+						return opts
+					}(),
+					Value: []*descriptorpb.EnumValueDescriptorProto{
+						{Name: proto.String("FAILURE_UNSPECIFIED"), Number: proto.Int32(0)},
+						{Name: proto.String("INSUFFICIENT_FUNDS"), Number: proto.Int32(1)},
+					},
+				}, {
+					// nested ErrorReason, not to collide with the top-level one
+					Name: proto.String("ErrorReason"),
+					Value: []*descriptorpb.EnumValueDescriptorProto{
+						{Name: proto.String("ERROR_REASON_UNSPECIFIED"), Number: proto.Int32(0)},
+						{Name: proto.String("WALLET_EMPTY"), Number: proto.Int32(1)},
+					},
+				}},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+				Location: []*descriptorpb.SourceCodeInfo_Location{{
+					Path:            []int32{5, 0, 2, 1}, // enum_type[0].value[1]
+					Span:            []int32{0, 0, 0},
+					LeadingComments: proto.String(" The wallet has no more Redlines available.\n"),
+				}},
+			},
+		}},
+	}
+
+	gen, err := protogen.Options{}.New(req)
+	require.NoError(t, err)
+
+	g := generateFile(gen, gen.FilesByPath["wallet/v1/errors.proto"])
+	require.NotNil(t, g)
+
+	got, err := g.Content()
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "errors_errdetails.pb.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
+
+func TestProto3Optional(t *testing.T) {
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{})
+	require.NoError(t, err)
+	require.NoError(t, run(gen))
+
+	features := gen.Response().GetSupportedFeatures()
+	require.NotZero(t, features&uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL),
+		"expected proto3 optional to be supported")
+}
+
+func TestOptionsPackage(t *testing.T) {
+	enumOptions := &descriptorpb.EnumOptions{}
+	proto.SetExtension(enumOptions, errdetailspb.E_Reasons, &errdetailspb.ReasonsOptions{
+		Domain:      "wallet.platform.test",
+		DefaultCode: code.Code_FAILED_PRECONDITION,
+	})
+	valueOptions := &descriptorpb.EnumValueOptions{}
+	proto.SetExtension(valueOptions, errdetailspb.E_Reason, &errdetailspb.ReasonOptions{
+		Code:    code.Code_RESOURCE_EXHAUSTED,
+		Message: "wallet has no more Redlines available",
+	})
+
+	// the plugin gets the options as unknown fields, not linking errdetailspb
+	unlinked := func(m proto.Message) proto.Message {
+		b, err := proto.Marshal(m)
+		require.NoError(t, err)
+
+		u := m.ProtoReflect().New().Interface()
+		require.NoError(t, proto.UnmarshalOptions{Resolver: new(protoregistry.Types)}.Unmarshal(b, u))
+		return u
+	}
+
+	reasons := readExtensions(unlinked(enumOptions))
+	domain, _ := reasons.string(domainField)
+	require.Equal(t, "wallet.platform.test", domain)
+	defaultCode, _ := reasons.code(defaultCodeField)
+	require.Equal(t, codes.FailedPrecondition, defaultCode)
+
+	reason := readExtensions(unlinked(valueOptions))
+	c, _ := reason.code(codeField)
+	require.Equal(t, codes.ResourceExhausted, c)
+	msg, _ := reason.string(messageField)
+	require.Equal(t, "wallet has no more Redlines available", msg)
+}
+
+// appendOptions appends an occurrence of the errdetails options extension.
+func appendOptions(b []byte, opts []byte) []byte {
+	b = protowire.AppendTag(b, optionsField, protowire.BytesType)
+	return protowire.AppendBytes(b, opts)
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendCode(b []byte, num protowire.Number, code uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, code)
+}
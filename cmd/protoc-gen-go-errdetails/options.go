@@ -0,0 +1,103 @@
+package main
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Field numbers of the options declared in errdetails/options.proto, being a
+// message extension of the enum and enum value options.
+//
+// The extensions are read from the unknown fields of the options, such that
+// the plugin doesn't depend on Go code generated from options.proto.
+const (
+	// optionsField is the field number of both the errdetails.reasons and the
+	// errdetails.reason extensions.
+	optionsField protowire.Number = 52100
+
+	// fields of errdetails.ReasonsOptions
+	domainField      protowire.Number = 1
+	defaultCodeField protowire.Number = 2
+
+	// fields of errdetails.ReasonOptions
+	codeField    protowire.Number = 1
+	messageField protowire.Number = 2
+)
+
+// extensions holds the raw fields of the errdetails options message by field
+// number, the last occurrence of a field taking precedence the same as when
+// occurrences of the message are merged.
+type extensions map[protowire.Number]rawField
+
+// rawField is a field of a message, having the length prefix of a bytes
+// field consumed.
+type rawField struct {
+	num   protowire.Number
+	typ   protowire.Type
+	value []byte
+}
+
+func readExtensions(opts protoreflect.ProtoMessage) extensions {
+	exts := extensions{}
+	if opts == nil {
+		return exts
+	}
+
+	for _, f := range readFields(opts.ProtoReflect().GetUnknown()) {
+		if f.num != optionsField || f.typ != protowire.BytesType {
+			continue
+		}
+		for _, f := range readFields(f.value) {
+			exts[f.num] = f
+		}
+	}
+
+	return exts
+}
+
+// readFields reads the raw fields of a message, in order.
+func readFields(b []byte) []rawField {
+	var fields []rawField
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			break
+		}
+		b = b[n:]
+
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			break
+		}
+
+		f := rawField{num: num, typ: typ, value: b[:m]}
+		if typ == protowire.BytesType {
+			f.value, _ = protowire.ConsumeBytes(f.value)
+		}
+		fields = append(fields, f)
+		b = b[m:]
+	}
+
+	return fields
+}
+
+func (e extensions) string(num protowire.Number) (string, bool) {
+	f, ok := e[num]
+	if !ok || f.typ != protowire.BytesType {
+		return "", false
+	}
+	return string(f.value), true
+}
+
+func (e extensions) code(num protowire.Number) (codes.Code, bool) {
+	f, ok := e[num]
+	if !ok || f.typ != protowire.VarintType {
+		return codes.Unknown, false
+	}
+	v, n := protowire.ConsumeVarint(f.value)
+	if n < 0 || v > uint64(codes.Unauthenticated) {
+		return codes.Unknown, false
+	}
+	return codes.Code(v), true
+}
@@ -0,0 +1,196 @@
+// Package dbclassify classifies errors of database drivers into errdetails
+// errors, to be registered with errdetails.RegisterClassifier:
+//
+//	errdetails.RegisterClassifier(dbclassify.Classifier)
+//
+// Driver errors are recognized by small interfaces, such that this module does
+// not depend on any database driver: PostgreSQL errors by their SQLSTATE, as of
+// lib/pq and pgx, and MySQL errors by their error number. As the MySQLError of
+// go-sql-driver/mysql has its error number as a field rather than a method, it
+// is recognized by its exact shape as a fallback.
+package dbclassify
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ClaudiaJ/errdetails"
+	pb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// retryDelay is the delay suggested by RetryInfo details of errors worth
+// retrying, like serialization failures.
+const retryDelay = 100 * time.Millisecond
+
+// Classifier classifies database errors with Classify.
+var Classifier errdetails.Classifier = errdetails.ClassifierFunc(Classify)
+
+// sqlStateError is a PostgreSQL error, like pq.Error and pgconn.PgError.
+type sqlStateError interface {
+	error
+	SQLState() string
+}
+
+// mysqlError is a MySQL error providing its error number.
+type mysqlError interface {
+	error
+	Number() uint16
+}
+
+// class of database error, regardless of the database.
+type class int
+
+const (
+	unclassified class = iota
+	uniqueViolation
+	foreignKeyViolation
+	serializationFailure
+	lockTimeout
+)
+
+// sqlStates classifies PostgreSQL errors by SQLSTATE.
+var sqlStates = map[string]class{
+	"23505": uniqueViolation,
+	"23503": foreignKeyViolation,
+	"40001": serializationFailure,
+	"40P01": serializationFailure, // deadlock detected
+}
+
+// mysqlNumbers classifies MySQL errors by error number.
+var mysqlNumbers = map[uint16]class{
+	1062: uniqueViolation,      // ER_DUP_ENTRY
+	1451: foreignKeyViolation,  // ER_ROW_IS_REFERENCED_2
+	1452: foreignKeyViolation,  // ER_NO_REFERENCED_ROW_2
+	1213: serializationFailure, // ER_LOCK_DEADLOCK
+	1205: lockTimeout,          // ER_LOCK_WAIT_TIMEOUT
+}
+
+// Classify classifies database errors as follows, or returns nil for errors
+// of any other kind:
+//
+//   - sql.ErrNoRows is NotFound
+//   - unique violations are AlreadyExists having ResourceInfo details
+//   - foreign key violations are FailedPrecondition having PreconditionFailure details
+//   - serialization failures and deadlocks are Aborted having RetryInfo details
+//   - lock wait timeouts are DeadlineExceeded
+//
+// The table and constraint violated are described in details when the driver
+// reports them.
+func Classify(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errdetails.WithDetails(err, errdetails.Code(codes.NotFound))
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch classify(e) {
+		case uniqueViolation:
+			table, constraint := violated(e)
+			return errdetails.WithDetails(err,
+				errdetails.Code(codes.AlreadyExists),
+				errdetails.Resource(&pb.ResourceInfo{
+					ResourceType: table,
+					Description:  description("unique", constraint),
+				}),
+			)
+		case foreignKeyViolation:
+			table, constraint := violated(e)
+			return errdetails.WithDetails(err,
+				errdetails.Code(codes.FailedPrecondition),
+				errdetails.PreconditionFailure(&pb.PreconditionFailure_Violation{
+					Type:        "FOREIGN_KEY",
+					Subject:     table,
+					Description: description("foreign key", constraint),
+				}),
+			)
+		case serializationFailure:
+			return errdetails.WithDetails(err,
+				errdetails.Code(codes.Aborted),
+				errdetails.RetryDelay(retryDelay),
+			)
+		case lockTimeout:
+			return errdetails.WithDetails(err, errdetails.Code(codes.DeadlineExceeded))
+		}
+	}
+
+	return nil
+}
+
+// classify a single layer of a wrapped error.
+func classify(err error) class {
+	switch e := err.(type) {
+	case sqlStateError:
+		return sqlStates[e.SQLState()]
+	case mysqlError:
+		return mysqlNumbers[e.Number()]
+	}
+
+	if number, ok := mysqlNumber(err); ok {
+		return mysqlNumbers[number]
+	}
+
+	return unclassified
+}
+
+// mysqlNumber gets the error number of an error shaped exactly like the
+// *mysql.MySQLError of go-sql-driver/mysql, the fallback for a driver error
+// not providing its error number by method.
+func mysqlNumber(err error) (uint16, bool) {
+	t := reflect.TypeOf(err)
+	if t.Kind() != reflect.Ptr || t.Elem().Name() != "MySQLError" {
+		return 0, false
+	}
+
+	number, ok := field(err, "Number")
+	if !ok || number.Kind() != reflect.Uint16 {
+		return 0, false
+	}
+	if msg, ok := field(err, "Message"); !ok || msg.Kind() != reflect.String {
+		return 0, false
+	}
+
+	return uint16(number.Uint()), true
+}
+
+// violated reports the table and constraint violated, as named by fields of
+// either pgconn.PgError or pq.Error.
+func violated(err error) (table, constraint string) {
+	return stringField(err, "TableName", "Table"), stringField(err, "ConstraintName", "Constraint")
+}
+
+func description(kind, constraint string) string {
+	if constraint == "" {
+		return kind + " constraint violated"
+	}
+	return fmt.Sprintf("%s constraint %q violated", kind, constraint)
+}
+
+// field gets the named field of an error, if it's a struct or pointer to one.
+func field(err error, name string) (reflect.Value, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	f := v.FieldByName(name)
+	return f, f.IsValid()
+}
+
+// stringField gets the first of the named string fields of an error being set.
+func stringField(err error, names ...string) string {
+	for _, name := range names {
+		if f, ok := field(err, name); ok && f.Kind() == reflect.String && f.String() != "" {
+			return f.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package dbclassify
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+// testPgError has the same shape as pgconn.PgError.
+type testPgError struct {
+	Code           string
+	Message        string
+	TableName      string
+	ConstraintName string
+}
+
+func (e *testPgError) Error() string    { return "ERROR: " + e.Message + " (SQLSTATE " + e.Code + ")" }
+func (e *testPgError) SQLState() string { return e.Code }
+
+// MySQLError has the same name and shape as mysql.MySQLError.
+type MySQLError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *MySQLError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+// testNumberError provides its MySQL error number by method.
+type testNumberError struct {
+	number uint16
+}
+
+func (e testNumberError) Error() string  { return fmt.Sprintf("Error %d", e.number) }
+func (e testNumberError) Number() uint16 { return e.number }
+
+// testUnrelatedError has an error number unrelated to MySQL.
+type testUnrelatedError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *testUnrelatedError) Error() string { return e.Message }
+
+func TestClassifyUniqueViolation(t *testing.T) {
+	for name, dberr := range map[string]error{
+		"postgres": &testPgError{Code: "23505", Message: "duplicate key", TableName: "wallets", ConstraintName: "wallets_pkey"},
+		"mysql":    &MySQLError{Number: 1062, Message: "Duplicate entry '123' for key 'PRIMARY'"},
+		"number":   testNumberError{number: 1062},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Classify(fmt.Errorf("create wallet: %w", dberr))
+			require.True(t, errors.Is(err, errdetails.ErrAlreadyExists), "expected error to be ErrAlreadyExists")
+			require.True(t, errors.Is(err, dberr), "expected error to wrap driver error")
+
+			var resErr errdetails.ResourceInfoError
+			require.True(t, errors.As(err, &resErr), "expected error to be ResourceInfoError")
+			if name == "postgres" {
+				require.Equal(t, "wallets", resErr.GetResourceType())
+				require.Equal(t, `unique constraint "wallets_pkey" violated`, resErr.GetDescription())
+			}
+		})
+	}
+}
+
+func TestClassifyForeignKeyViolation(t *testing.T) {
+	for name, dberr := range map[string]error{
+		"postgres":      &testPgError{Code: "23503", TableName: "transfers", ConstraintName: "transfers_wallet_fkey"},
+		"mysql parent":  &MySQLError{Number: 1451},
+		"mysql missing": &MySQLError{Number: 1452},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Classify(dberr)
+			require.True(t, errors.Is(err, errdetails.ErrFailedPrecondition), "expected error to be ErrFailedPrecondition")
+
+			var preErr errdetails.FailedPreconditionError
+			require.True(t, errors.As(err, &preErr), "expected error to be FailedPreconditionError")
+			require.Len(t, preErr.GetViolations(), 1)
+			require.Equal(t, "FOREIGN_KEY", preErr.GetViolations()[0].GetType())
+			if name == "postgres" {
+				require.Equal(t, "transfers", preErr.GetViolations()[0].GetSubject())
+			}
+		})
+	}
+}
+
+func TestClassifySerializationFailure(t *testing.T) {
+	for name, dberr := range map[string]error{
+		"postgres serialization": &testPgError{Code: "40001"},
+		"postgres deadlock":      &testPgError{Code: "40P01"},
+		"mysql deadlock":         &MySQLError{Number: 1213},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Classify(dberr)
+			require.True(t, errors.Is(err, errdetails.ErrAborted), "expected error to be ErrAborted")
+
+			var retryErr errdetails.RetriableError
+			require.True(t, errors.As(err, &retryErr), "expected error to be RetriableError")
+			require.Equal(t, 100*time.Millisecond, retryErr.GetRetryDelay())
+		})
+	}
+}
+
+func TestClassifyLockTimeout(t *testing.T) {
+	err := Classify(&MySQLError{Number: 1205})
+	require.True(t, errors.Is(err, errdetails.ErrDeadlineExceeded), "expected error to be ErrDeadlineExceeded")
+
+	var retryErr errdetails.RetriableError
+	require.False(t, errors.As(err, &retryErr), "expected error not to be RetriableError")
+}
+
+func TestClassifyNoRows(t *testing.T) {
+	err := Classify(fmt.Errorf("get wallet: %w", sql.ErrNoRows))
+	require.True(t, errors.Is(err, errdetails.ErrNotFound), "expected error to be ErrNotFound")
+	require.True(t, errors.Is(err, sql.ErrNoRows), "expected error to be sql.ErrNoRows")
+}
+
+func TestClassifyUnclassified(t *testing.T) {
+	require.Nil(t, Classify(errors.New("oops")))
+	require.Nil(t, Classify(&testPgError{Code: "42P01"}))
+	require.Nil(t, Classify(&MySQLError{Number: 1146}))
+	require.Nil(t, Classify(&testUnrelatedError{Number: 1062, Message: "oops"}))
+}
+
+func TestClassifier(t *testing.T) {
+	errdetails.RegisterClassifier(Classifier)
+
+	s := errdetails.ToStatus(fmt.Errorf("create wallet: %w", &testPgError{Code: "23505", Message: "duplicate key"}))
+	require.Equal(t, codes.AlreadyExists, s.Code())
+	require.Equal(t, "create wallet: ERROR: duplicate key (SQLSTATE 23505)", s.Message())
+}
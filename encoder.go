@@ -0,0 +1,240 @@
+package errdetails
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	contentType         = "application/json"
+	protobufContentType = "application/x-protobuf"
+	textContentType     = "text/plain; charset=utf-8"
+)
+
+// Encoder encodes a Status into the body of an error response.
+type Encoder interface {
+	// ContentType gets the media type of the encoded response body.
+	ContentType() string
+
+	// Encode encodes a Status into a response body.
+	Encode(*status.Status) ([]byte, error)
+}
+
+// Built-in Encoders.
+var (
+	// JSONEncoder encodes a Status as JSON, the same as ToJSON.
+	JSONEncoder Encoder = jsonEncoder{}
+
+	// ProblemEncoder encodes a Status as an RFC 9457 problem details JSON
+	// document, the same as ToProblemJSON.
+	ProblemEncoder Encoder = problemEncoder{}
+
+	// ProtobufEncoder encodes a Status as binary Protobuf.
+	ProtobufEncoder Encoder = protobufEncoder{}
+
+	// TextEncoder encodes a Status as human readable plain text, having the
+	// code and message on the first line followed by a line for each detail.
+	TextEncoder Encoder = textEncoder{}
+)
+
+type jsonEncoder struct{}
+
+// ContentType implements Encoder interface.
+func (jsonEncoder) ContentType() string {
+	return contentType
+}
+
+// Encode implements Encoder interface.
+func (jsonEncoder) Encode(s *status.Status) ([]byte, error) {
+	b, err := protojson.Marshal(s.Proto())
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, b); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+type protobufEncoder struct{}
+
+// ContentType implements Encoder interface.
+func (protobufEncoder) ContentType() string {
+	return protobufContentType
+}
+
+// Encode implements Encoder interface.
+func (protobufEncoder) Encode(s *status.Status) ([]byte, error) {
+	return proto.Marshal(s.Proto())
+}
+
+type textEncoder struct{}
+
+// ContentType implements Encoder interface.
+func (textEncoder) ContentType() string {
+	return textContentType
+}
+
+// Encode implements Encoder interface.
+func (textEncoder) Encode(s *status.Status) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "%s: %s\n", s.Code(), s.Message())
+
+	for _, detail := range s.Proto().GetDetails() {
+		msg, err := detail.UnmarshalNew()
+		if err != nil {
+			fmt.Fprintf(buf, "%s\n", detail.MessageName())
+			continue
+		}
+
+		b, err := prototext.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(buf, "%s: %s\n", detail.MessageName(), strings.TrimSpace(string(b)))
+	}
+
+	return buf.Bytes(), nil
+}
+
+type responseEncoder struct {
+	mu       sync.RWMutex
+	encoder  Encoder
+	encoders []Encoder
+}
+
+// negotiate chooses the registered Encoder most preferred by an Accept header,
+// or the default Encoder if none of them are acceptable. Encoders of a media
+// type refused by the Accept header with a quality of zero are never chosen,
+// unless every one of them is refused.
+func (e *responseEncoder) negotiate(accept string) Encoder {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	ranges := parseWeighted(strings.ToLower(accept))
+	candidates := append([]Encoder{e.encoder}, e.encoders...)
+
+	for _, mediaRange := range ranges {
+		if mediaRange.q <= 0 {
+			continue
+		}
+
+		// prefer the default Encoder should it match as well as any other
+		for _, enc := range candidates {
+			if mediaRangeMatches(mediaRange.value, enc.ContentType()) && !excluded(ranges, enc.ContentType()) {
+				return enc
+			}
+		}
+	}
+
+	// none are acceptable, but an error response is better than none at all,
+	// so long as it's not of a media type the client explicitly refused
+	for _, enc := range candidates {
+		if !excluded(ranges, enc.ContentType()) {
+			return enc
+		}
+	}
+
+	return e.encoder
+}
+
+// excluded reports whether a content type is refused with a quality of zero
+// by the most specific of the media ranges matching it.
+func excluded(ranges []weighted, contentType string) bool {
+	specificity, q := -1, 1.0
+	for _, mediaRange := range ranges {
+		if !mediaRangeMatches(mediaRange.value, contentType) {
+			continue
+		}
+
+		s := 2
+		switch {
+		case mediaRange.value == "*/*":
+			s = 0
+		case strings.HasSuffix(mediaRange.value, "/*"):
+			s = 1
+		}
+		if s > specificity {
+			specificity, q = s, mediaRange.q
+		}
+	}
+
+	return q <= 0
+}
+
+// mediaRangeMatches reports whether a media type is within a media range,
+// including the "*/*" range of any media type.
+func mediaRangeMatches(mediaRange, contentType string) bool {
+	return mediaRange == "*/*" || mediaTypeMatches(mediaRange, contentType)
+}
+
+// mediaTypeMatches reports whether a media type, ignoring any parameters, is
+// within a media range like "application/json" or "text/*".
+func mediaTypeMatches(mediaRange, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	if prefix := strings.TrimSuffix(mediaRange, "*"); prefix != mediaRange {
+		return strings.HasPrefix(mediaType, prefix)
+	}
+	return mediaType == mediaRange
+}
+
+var (
+	// encoder encodes error responses written by HandlerFunc.
+	encoder = &responseEncoder{
+		encoder:  JSONEncoder,
+		encoders: []Encoder{JSONEncoder, ProblemEncoder, ProtobufEncoder, TextEncoder},
+	}
+)
+
+// RegisterEncoder registers an Encoder for HandlerFunc to choose by the Accept
+// header of the request, replacing any Encoder registered for the same media
+// type.
+//
+// The JSONEncoder, ProblemEncoder, ProtobufEncoder, and TextEncoder are
+// registered by default.
+func RegisterEncoder(enc Encoder) {
+	encoder.mu.Lock()
+	defer encoder.mu.Unlock()
+
+	mediaType, _, _ := mime.ParseMediaType(enc.ContentType())
+	for idx, registered := range encoder.encoders {
+		if mediaTypeMatches(mediaType, registered.ContentType()) {
+			encoder.encoders[idx] = enc
+			return
+		}
+	}
+	encoder.encoders = append(encoder.encoders, enc)
+}
+
+// SetEncoder sets the package level Encoder used by HandlerFunc to encode
+// error responses when the request does not accept any registered Encoder in
+// particular.
+//
+// By default, or when set to nil, error responses are encoded as JSON by the
+// JSONEncoder.
+func SetEncoder(enc Encoder) {
+	if enc == nil {
+		enc = JSONEncoder
+	}
+
+	encoder.mu.Lock()
+	defer encoder.mu.Unlock()
+	encoder.encoder = enc
+}
@@ -0,0 +1,100 @@
+package errdetails
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestNegotiateEncoder(t *testing.T) {
+	testHandler(t)
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return New(codes.NotFound, "no such wallet",
+			Resource(&errdetails.ResourceInfo{
+				ResourceType: "wallet",
+				ResourceName: "123456789",
+			}),
+		)
+	})
+
+	for accept, want := range map[string]string{
+		"":                                    "application/json",
+		"*/*":                                 "application/json",
+		"application/*":                       "application/json",
+		"application/problem+json":            "application/problem+json",
+		"application/x-protobuf, */*;q=0.1":   "application/x-protobuf",
+		"text/html, text/plain;q=0.9":         "text/plain; charset=utf-8",
+		"text/*":                              "text/plain; charset=utf-8",
+		"image/png":                           "application/json",
+		"application/json;q=0, text/html":     "application/problem+json",
+		"*/*, application/json;q=0":           "application/problem+json",
+		"application/*, application/json;q=0": "application/problem+json",
+		"application/json;q=0.5, text/plain":  "text/plain; charset=utf-8",
+	} {
+		t.Run(accept, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", accept)
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusNotFound, rr.Code)
+			require.Equal(t, want, rr.Header().Get("Content-Type"))
+			require.Equal(t, []string{"Accept"}, rr.Header().Values("Vary"), "expected no Vary by Accept-Language without a catalog")
+
+			err := CheckResponse(rr.Result())
+			require.True(t, errors.Is(err, ErrNotFound), "expected error to be ErrNotFound")
+
+			if want != "text/plain; charset=utf-8" {
+				var resErr ResourceInfoError
+				require.True(t, errors.As(err, &resErr), "expected error to be ResourceInfoError")
+				require.Equal(t, "wallet", resErr.GetResourceType())
+			}
+		})
+	}
+}
+
+func TestTextEncoder(t *testing.T) {
+	testHandler(t)
+
+	b, err := TextEncoder.Encode(ToStatus(New(codes.NotFound, "no such wallet",
+		Resource(&errdetails.ResourceInfo{
+			ResourceType: "wallet",
+		}),
+	)))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, "NotFound: no such wallet", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], "google.rpc.ResourceInfo: "), lines[1])
+	require.Contains(t, lines[1], `"wallet"`)
+}
+
+func TestSetEncoder(t *testing.T) {
+	testHandler(t)
+
+	SetEncoder(TextEncoder)
+	t.Cleanup(func() { SetEncoder(nil) })
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return New(codes.NotFound, "no such wallet")
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+
+	rr = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/json")
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+}
@@ -41,11 +41,26 @@ var (
 //
 // This implementation is specific to gRPC Status codes, but the same example
 // can be applied for any other Flag-like wrapping.
+//
+// When enabled with SetStackCapture, the stack of the caller is captured into
+// DebugError details.
 func New(code codes.Code, msg string, details ...Details) error {
-	return WithDetails(&errCodeError{
+	return WithDetails(newCoded(code, msg), details...)
+}
+
+// newCoded creates an error having a Status Code, capturing the stack of the
+// caller of the constructor calling newCoded when enabled with SetStackCapture.
+func newCoded(code codes.Code, msg string) error {
+	var err error = &errCodeError{
 		Code:  code,
 		error: errors.New(msg),
-	}, details...)
+	}
+	if stackCaptureEnabled() {
+		// skip callers, newCoded, and the constructor
+		err = &errStack{error: err, pcs: callers(4)}
+	}
+
+	return err
 }
 
 // errCodeError enriches an error with status codes.
@@ -153,6 +168,7 @@ func (e *errHelpLink) GetLinks() []details.HelpLink {
 type BadRequestError interface {
 	error
 	WithViolation(violation ...details.FieldViolation) BadRequestError
+	WithPrefix(prefix FieldPath) BadRequestError
 	GetViolations() []details.FieldViolation
 }
 
@@ -188,6 +204,18 @@ func (e *errBadRequest) WithViolation(violations ...details.FieldViolation) BadR
 	return e
 }
 
+// WithPrefix re-roots the field of each violation under a prefix, e.g. when
+// the violations of a nested message are embedded in its parent request.
+func (e *errBadRequest) WithPrefix(prefix FieldPath) BadRequestError {
+	for idx, violation := range e.BadRequest.FieldViolations {
+		e.BadRequest.FieldViolations[idx] = &errdetails.BadRequest_FieldViolation{
+			Field:       string(prefix.Join(FieldPath(violation.GetField()))),
+			Description: violation.GetDescription(),
+		}
+	}
+	return e
+}
+
 func (e *errBadRequest) GetViolations() []details.FieldViolation {
 	violations := make([]details.FieldViolation, len(e.BadRequest.FieldViolations))
 	for k, v := range e.BadRequest.FieldViolations {
@@ -233,6 +261,16 @@ func (e *errInfo) Unwrap() error {
 	return e.error
 }
 
+// Is implements errors.Is, matches a target ReasonError having the same domain
+// and reason.
+func (e *errInfo) Is(target error) bool {
+	if v, ok := target.(*ReasonError); ok {
+		return v.GetDomain() == e.GetDomain() && v.GetReason() == e.GetReason()
+	}
+
+	return false
+}
+
 // LocalizedError is an error including a localized error message that is safe
 // to return to the user.
 type LocalizedError interface {
@@ -252,6 +290,59 @@ func (e *localizedError) Unwrap() error {
 	return e.error
 }
 
+// LocalizableError is an error having a message that can be localized to the
+// client's locale before it's returned to the user.
+type LocalizableError interface {
+	error
+	Localizer
+}
+
+var _ LocalizableError = (*errLocalizable)(nil)
+
+type errLocalizable struct {
+	error
+	id   string
+	args map[string]string
+}
+
+// Unwrap implements errors.Unwrap interface.
+func (e *errLocalizable) Unwrap() error {
+	return e.error
+}
+
+// Localize renders the message from the catalog with arguments interpolated
+// from the Metadata of any CausedError wrapped by the LocalizableError, and the
+// arguments of the LocalizableError itself.
+//
+// When localized by the package level Localize, the Metadata of a CausedError
+// wrapping the LocalizableError is considered too.
+func (e *errLocalizable) Localize(locale string, catalog Catalog) (details.LocalizedMessage, bool) {
+	return e.localize(e.error, locale, catalog)
+}
+
+// localize renders the message with arguments interpolated from the Metadata
+// of the first CausedError found from the root of the wrapped error.
+func (e *errLocalizable) localize(root error, locale string, catalog Catalog) (details.LocalizedMessage, bool) {
+	args := map[string]string{}
+
+	var causedErr CausedError
+	if errors.As(root, &causedErr) {
+		for k, v := range causedErr.GetMetadata() {
+			args[k] = v
+		}
+	}
+	for k, v := range e.args {
+		args[k] = v
+	}
+
+	msg, ok := catalog.Message(locale, e.id, args)
+	if !ok {
+		return nil, false
+	}
+
+	return &errdetails.LocalizedMessage{Locale: locale, Message: msg}, true
+}
+
 // FailedPreconditionError is an error describing what preconditions have failed.
 //
 // An example being a Terms of Service acknowledgement that may be required
@@ -64,6 +64,14 @@ func ExampleDebug() {
 	)
 }
 
+func ExampleStack() {
+	// stack entries of DebugInfo point at the caller of Stack, and are only
+	// symbolized once the error is encoded
+	errdetails.New(codes.Internal, "impossible error reached",
+		errdetails.Stack(),
+	)
+}
+
 func ExampleHelp() {
 	errdetails.New(codes.PermissionDenied, "access denied",
 		errdetails.Help(&detailspb.Help_Link{
@@ -308,3 +316,14 @@ func ExampleWithRetryDelay() {
 	// error is errdetails.RetriableError
 	// with recommended delay: 10m0s
 }
+
+func ExampleReason() {
+	// a ReasonError declares a precise reason of failure within a domain
+	errWalletEmpty := errdetails.Reason("wallet.platform.test", "WALLET_EMPTY", codes.ResourceExhausted)
+
+	err := errWalletEmpty.New("no more Redlines available")
+
+	fmt.Println(errors.Is(err, errWalletEmpty), errors.Is(err, errdetails.ErrResourceExhausted))
+	//output:
+	// true true
+}
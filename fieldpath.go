@@ -0,0 +1,203 @@
+package errdetails
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldPath is a path to a field of a request, in the form of the Field of
+// FieldViolation details: a sequence of dot-separated field names, where list
+// items are indexed like "addresses[0].city", and map values are keyed like
+// `labels["env"]`.
+type FieldPath string
+
+// Child appends a field name to the path.
+func (p FieldPath) Child(name string) FieldPath {
+	if p == "" {
+		return FieldPath(name)
+	}
+	return p + "." + FieldPath(name)
+}
+
+// Index appends the index of a list item to the path.
+func (p FieldPath) Index(idx int) FieldPath {
+	return p + "[" + FieldPath(strconv.Itoa(idx)) + "]"
+}
+
+// Key appends the key of a map value to the path.
+func (p FieldPath) Key(key string) FieldPath {
+	return p + "[" + FieldPath(strconv.Quote(key)) + "]"
+}
+
+// Join appends another path relative to this one, e.g. to re-root the field
+// violations of a nested message.
+func (p FieldPath) Join(rel FieldPath) FieldPath {
+	switch {
+	case p == "":
+		return rel
+	case rel == "":
+		return p
+	case strings.HasPrefix(string(rel), "["):
+		return p + rel
+	}
+	return p + "." + rel
+}
+
+// String implements fmt.Stringer interface.
+func (p FieldPath) String() string {
+	return string(p)
+}
+
+// PathElementKind is the kind of a PathElement.
+type PathElementKind int
+
+// Kinds of PathElement.
+const (
+	// FieldElement is the name of a field.
+	FieldElement PathElementKind = iota
+	// IndexElement is the index of a list item.
+	IndexElement
+	// KeyElement is the key of a map value.
+	KeyElement
+)
+
+// PathElement is a single element of a FieldPath.
+type PathElement struct {
+	Kind PathElementKind
+	// Name is the name of a field, or the key of a map value.
+	Name string
+	// Index is the index of a list item.
+	Index int
+}
+
+// ParseFieldPath parses a FieldPath into its elements.
+func ParseFieldPath(path string) ([]PathElement, error) {
+	var elems []PathElement
+
+	for rest := path; rest != ""; {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: unterminated subscript", path)
+			}
+
+			if rest[1] == '"' {
+				quoted, err := strconv.QuotedPrefix(rest[1:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid field path %q: %w", path, err)
+				}
+				key, _ := strconv.Unquote(quoted)
+
+				rest = rest[1+len(quoted):]
+				if !strings.HasPrefix(rest, "]") {
+					return nil, fmt.Errorf("invalid field path %q: unterminated subscript", path)
+				}
+				elems = append(elems, PathElement{Kind: KeyElement, Name: key})
+				rest = rest[1:]
+				continue
+			}
+
+			idx, err := strconv.Atoi(rest[1:end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid field path %q: invalid index %q", path, rest[1:end])
+			}
+			elems = append(elems, PathElement{Kind: IndexElement, Index: idx})
+			rest = rest[end+1:]
+
+		case len(elems) > 0 && rest[0] != '.':
+			return nil, fmt.Errorf("invalid field path %q: expected '.' or '[' after %q", path, path[:len(path)-len(rest)])
+
+		default:
+			if len(elems) > 0 {
+				rest = rest[1:]
+			}
+
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid field path %q: empty field name", path)
+			}
+
+			elems = append(elems, PathElement{Kind: FieldElement, Name: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	return elems, nil
+}
+
+// FieldPathOf builds a FieldPath from its elements.
+func FieldPathOf(elems ...PathElement) FieldPath {
+	var p FieldPath
+	for _, elem := range elems {
+		switch elem.Kind {
+		case IndexElement:
+			p = p.Index(elem.Index)
+		case KeyElement:
+			p = p.Key(elem.Name)
+		default:
+			p = p.Child(elem.Name)
+		}
+	}
+	return p
+}
+
+// JSONPointer converts the path to an RFC 6901 JSON Pointer, e.g.
+// "/addresses/0/city".
+func (p FieldPath) JSONPointer() (string, error) {
+	elems, err := ParseFieldPath(string(p))
+	if err != nil {
+		return "", err
+	}
+
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+
+	var b strings.Builder
+	for _, elem := range elems {
+		b.WriteByte('/')
+		if elem.Kind == IndexElement {
+			b.WriteString(strconv.Itoa(elem.Index))
+			continue
+		}
+		b.WriteString(escaper.Replace(elem.Name))
+	}
+	return b.String(), nil
+}
+
+// FromJSONPointer converts an RFC 6901 JSON Pointer to a FieldPath.
+//
+// A JSON Pointer doesn't tell list items from map values, so reference tokens
+// that are non-negative integers become list indexes, other tokens that are
+// not valid field names become map keys, and the rest become field names.
+func FromJSONPointer(ptr string) (FieldPath, error) {
+	if ptr == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return "", errors.New("invalid JSON Pointer: must be empty or start with '/'")
+	}
+
+	unescaper := strings.NewReplacer("~1", "/", "~0", "~")
+
+	var p FieldPath
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = unescaper.Replace(token)
+
+		if idx, err := strconv.Atoi(token); err == nil && idx >= 0 && strconv.Itoa(idx) == token {
+			p = p.Index(idx)
+			continue
+		}
+		if token == "" || strings.ContainsAny(token, `.[]"`) {
+			p = p.Key(token)
+			continue
+		}
+		p = p.Child(token)
+	}
+
+	return p, nil
+}
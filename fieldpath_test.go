@@ -0,0 +1,86 @@
+package errdetails
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestFieldPath(t *testing.T) {
+	p := FieldPath("").Child("book").Child("authors").Index(2).Child("labels").Key(`a "b"`)
+	require.Equal(t, FieldPath(`book.authors[2].labels["a \"b\""]`), p)
+
+	require.Equal(t, FieldPath("a.b"), FieldPath("a").Join("b"))
+	require.Equal(t, FieldPath("a[0]"), FieldPath("a").Join("[0]"))
+	require.Equal(t, FieldPath("b"), FieldPath("").Join("b"))
+	require.Equal(t, FieldPath("a"), FieldPath("a").Join(""))
+}
+
+func TestParseFieldPath(t *testing.T) {
+	elems, err := ParseFieldPath(`book.authors[2].labels["a.b[c]"]`)
+	require.NoError(t, err)
+	require.Equal(t, []PathElement{
+		{Kind: FieldElement, Name: "book"},
+		{Kind: FieldElement, Name: "authors"},
+		{Kind: IndexElement, Index: 2},
+		{Kind: FieldElement, Name: "labels"},
+		{Kind: KeyElement, Name: "a.b[c]"},
+	}, elems)
+	require.Equal(t, FieldPath(`book.authors[2].labels["a.b[c]"]`), FieldPathOf(elems...))
+
+	elems, err = ParseFieldPath("")
+	require.NoError(t, err)
+	require.Empty(t, elems)
+
+	for _, invalid := range []string{".a", "a.", "a..b", "a[", "a[]", "a[-1]", "a[x]", `a["x]`, `a["x"`, "a[0]b"} {
+		_, err := ParseFieldPath(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	ptr, err := FieldPath(`book.authors[2].labels["a/b~c"]`).JSONPointer()
+	require.NoError(t, err)
+	require.Equal(t, "/book/authors/2/labels/a~1b~0c", ptr)
+
+	p, err := FromJSONPointer(ptr)
+	require.NoError(t, err)
+	require.Equal(t, FieldPath(`book.authors[2].labels.a/b~c`), p)
+
+	p, err = FromJSONPointer("/labels/a.b/")
+	require.NoError(t, err)
+	require.Equal(t, FieldPath(`labels["a.b"][""]`), p)
+
+	p, err = FromJSONPointer("")
+	require.NoError(t, err)
+	require.Equal(t, FieldPath(""), p)
+
+	_, err = FromJSONPointer("book")
+	require.Error(t, err)
+}
+
+func TestBadRequestWithPrefix(t *testing.T) {
+	testHandler(t)
+
+	violation := &errdetails.BadRequest_FieldViolation{Field: "city", Description: "must be set"}
+	nested := WithBadRequest(New(codes.InvalidArgument, "invalid address"),
+		violation,
+		&errdetails.BadRequest_FieldViolation{Field: "[0]", Description: "must be set"},
+	)
+
+	err := error(nested.WithPrefix(FieldPath("addresses").Index(1)))
+
+	var badErr BadRequestError
+	require.True(t, errors.As(err, &badErr), "expected error to be BadRequestError")
+
+	violations := badErr.GetViolations()
+	require.Len(t, violations, 2)
+	require.Equal(t, "addresses[1].city", violations[0].GetField())
+	require.Equal(t, "addresses[1][0]", violations[1].GetField())
+
+	// violations given are left as they were
+	require.Equal(t, "city", violation.GetField())
+}
@@ -8,44 +8,59 @@ import (
 	"io"
 	"net/http"
 
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	statuspb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/types/known/anypb"
 )
 
-const contentType = "application/json"
-
 // HandlerFunc type is an adapter to allow the use of ordinary functions as HTTP handlers.
 type HandlerFunc func(http.ResponseWriter, *http.Request) error
 
-// ServeHTTP serves a JSON error response back to client if the Handler would return an error.
+// ServeHTTP serves an error response back to client if the Handler would
+// return an error, encoded by the registered Encoder most preferred by the
+// client's Accept header, or by the package level Encoder as JSON by default.
+//
+// Errors are localized to the client's Accept-Language with the package level
+// Catalog, if one is set.
+//
+// Details not safe to share with the client are redacted by the package level
+// Redactor, see SetRedactor.
 //
-// Note of caution: Masking or otherwise distinguishing details safe to share
-// to end client is an exercise left to the implementor.
+// The Retry-After header is set from RetriableError details, and the RateLimit
+// and RateLimit-Policy headers from FailedQuotaError details, see
+// SetRateLimitMapper.
 func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	verr := fn(w, r)
 	if verr == nil {
 		return
 	}
 
-	statusCode := http.StatusInternalServerError
+	verr = Localize(Classify(verr), ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+	s := redactor.Redact(toStatus(verr))
+
+	// errors not describing their HTTP status code get one from the Status Code
+	statusCode := runtime.HTTPStatusFromCode(s.Code())
 
 	var sterr hasStatusCode
 	if errors.As(verr, &sterr) {
 		statusCode = sterr.StatusCode()
 	}
 
-	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	if catalog.get() != nil {
+		// localized messages depend on the locales preferred by the client
+		w.Header().Add("Vary", "Accept-Language")
+	}
 
-	b, err := ToJSON(verr)
+	enc := encoder.negotiate(r.Header.Get("Accept"))
+	b, err := enc.Encode(s)
 	if err != nil {
-		handler.Handle(fmt.Errorf("failed to encode error to JSON: %w", err))
+		handler.Handle(fmt.Errorf("failed to encode error response: %w", err))
 
+		w.Header().Set("Content-Type", contentType)
 		w.WriteHeader(http.StatusInternalServerError)
 		if err := json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  codes.Internal,
@@ -56,10 +71,11 @@ func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := json.RawMessage(b)
+	setRetryHeaders(w.Header(), s)
+	w.Header().Set("Content-Type", enc.ContentType())
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(&resp); err != nil {
-		handler.Handle(fmt.Errorf("failed to write JSON encoded error to ResponseWriter: %w", err))
+	if _, err := w.Write(b); err != nil {
+		handler.Handle(fmt.Errorf("failed to write encoded error to ResponseWriter: %w", err))
 	}
 }
 
@@ -70,45 +86,38 @@ func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // this package to be reconstructed from http response body the same as all of
 // the error types provided by this module.
 //
-// This will go away whenever I can figure out how to acheive this with protoreflect.
+// Deprecated: register the message type with RegisterDetail instead, which is
+// considered by every decoder without having to provide it on each call.
 type DetailsMapper interface {
 	Map(protoreflect.ProtoMessage) Details
 }
 
 // ToJSON writes an error as JSON with details in-tact such that it can be
-// mostly recovered with FromJSON.
+// mostly recovered with FromJSON, less anything redacted by the package level
+// Redactor.
+//
+// The JSON is compact, having no whitespace between tokens, unlike the output
+// of protojson varying between builds, such that the same error is written as
+// the same bytes, even by another build of another program.
 func ToJSON(from error) ([]byte, error) {
-	// become a Status one way or another
-	var toStatus statusError
-	if !errors.As(from, &toStatus) {
-		toStatus = &errCodeError{error: from, Code: codes.Unknown}
+	b, err := protojson.Marshal(redactor.Redact(ToStatus(from)).Proto())
+	if err != nil {
+		return nil, err
 	}
 
-	p := status.Convert(toStatus).Proto()
-	for {
-		// turn error details into protobuf details
-		if msg, ok := from.(protoreflect.ProtoMessage); ok {
-			any, err := anypb.New(msg)
-			if err != nil {
-				return nil, err
-			}
-			p.Details = append(p.Details, any)
-		}
-		// unwrap and move on the next
-		if from = errors.Unwrap(from); from == nil {
-			break
-		}
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, b); err != nil {
+		return nil, err
 	}
-
-	return protojson.Marshal(p)
+	return buf.Bytes(), nil
 }
 
 // FromJSON reads JSON fom a Reader like a response Body, and makes best effort
 // to reconstruct the wrapped error from gRPC Status such that errors.As and
 // errors.Is may still be satisfied by the error interface types.
 //
-// For any expected error not already accomodated by this package, you can
-// provide optional DetailsMappers.
+// For any expected error not already accomodated by this package, register the
+// message type with RegisterDetail.
 //
 // If the Map method of a DetailsMapper returns an implementation of Details
 // wrapper, the error is further wrapped by the mapped wrapper.
@@ -123,50 +132,7 @@ func FromJSON(r io.Reader, mappers ...DetailsMapper) error {
 		return err
 	}
 
-	sterr := New(codes.Code(s.Code), s.Message)
-
-	for _, detail := range s.Details {
-		pb, err := anypb.UnmarshalNew(detail, proto.UnmarshalOptions{})
-		if err != nil {
-			return err
-		}
-		// consider arbitrary client-provided error types too
-		// TODO: How to better leverage protoreflect?
-		for _, mapper := range mappers {
-			if wrapper := mapper.Map(pb); wrapper != nil {
-				sterr = wrapper.Wrap(sterr)
-			}
-		}
-
-		switch msg := pb.(type) {
-		case *errdetails.BadRequest:
-			sterr = &errBadRequest{error: sterr, BadRequest: msg}
-		case *errdetails.DebugInfo:
-			sterr = &errDebugInfo{error: sterr, DebugInfo: msg}
-		case *errdetails.ErrorInfo:
-			sterr = &errInfo{error: sterr, ErrorInfo: msg}
-		case *errdetails.Help:
-			sterr = &errHelpLink{error: sterr, Help: msg}
-		case *errdetails.LocalizedMessage:
-			sterr = &localizedError{error: sterr, LocalizedMessage: msg}
-		case *errdetails.PreconditionFailure:
-			sterr = &errPreconditionFailed{error: sterr, PreconditionFailure: msg}
-		case *errdetails.QuotaFailure:
-			sterr = &errQuotaFailure{error: sterr, QuotaFailure: msg}
-		case *errdetails.RequestInfo:
-			sterr = &errRequestInfo{error: sterr, RequestInfo: msg}
-		case *errdetails.ResourceInfo:
-			sterr = &errResourceInfo{error: sterr, ResourceInfo: msg}
-		case *errdetails.RetryInfo:
-			sterr = &errRetryInfo{error: sterr, RetryInfo: msg}
-		default:
-			sterr = WithDetails(sterr, wrapperFunc(func(err error) error {
-				return &arbitraryError{error: err, ProtoMessage: msg}
-			}))
-		}
-	}
-
-	return sterr
+	return FromStatus(status.FromProto(s), mappers...)
 }
 
 // arbitraryError just enables us to put our protobuf details back into some
@@ -194,18 +160,6 @@ func (e *arbitraryError) Unwrap() error {
 	return e.error
 }
 
-// statusError is a neat little trick used in gRPC status module to enable
-// errors to self-describe a conversion to Status.
-type statusError interface {
-	error
-	GRPCStatus() *status.Status
-}
-
 type hasStatusCode interface {
 	StatusCode() int
 }
-
-type localizable interface {
-	// Localize renders a localizable error message to the client-requested locale.
-	Localize(r *http.Request) (LocalizedError, error)
-}
@@ -1,6 +1,8 @@
 package errdetails
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -129,3 +131,56 @@ func testHandler(t *testing.T) {
 		t.Fatal(err)
 	}))
 }
+
+func TestJSONRoundTrip(t *testing.T) {
+	testHandler(t)
+
+	err := New(codes.FailedPrecondition, "wallet is frozen",
+		Cause(&errdetails.ErrorInfo{
+			Reason:   "WALLET_FROZEN",
+			Domain:   "wallet.platform.test",
+			Metadata: map[string]string{"wallet": "123", "owner": "auth0|123"},
+		}),
+		PreconditionFailure(&errdetails.PreconditionFailure_Violation{
+			Type:    "STATE",
+			Subject: "wallets/123",
+		}),
+		Help(&errdetails.Help_Link{Url: "url1", Description: "desc1"}),
+		RequestInfo(&errdetails.RequestInfo{RequestId: "123456789"}),
+	)
+
+	b, jerr := ToJSON(err)
+	require.NoError(t, jerr)
+
+	// outermost layer first
+	require.JSONEq(t, `{
+		"code": 9,
+		"message": "wallet is frozen",
+		"details": [{
+			"@type": "type.googleapis.com/google.rpc.RequestInfo",
+			"requestId": "123456789"
+		}, {
+			"@type": "type.googleapis.com/google.rpc.Help",
+			"links": [{"url": "url1", "description": "desc1"}]
+		}, {
+			"@type": "type.googleapis.com/google.rpc.PreconditionFailure",
+			"violations": [{"type": "STATE", "subject": "wallets/123"}]
+		}, {
+			"@type": "type.googleapis.com/google.rpc.ErrorInfo",
+			"reason": "WALLET_FROZEN",
+			"domain": "wallet.platform.test",
+			"metadata": {"owner": "auth0|123", "wallet": "123"}
+		}]
+	}`, string(b))
+
+	// canonical form, having no whitespace whatever the build of protojson
+	compact := new(bytes.Buffer)
+	require.NoError(t, json.Compact(compact, b))
+	require.Equal(t, compact.String(), string(b))
+
+	for i := 0; i < 3; i++ {
+		rebuilt, jerr := ToJSON(FromJSON(bytes.NewReader(b)))
+		require.NoError(t, jerr)
+		require.Equal(t, string(b), string(rebuilt))
+	}
+}
@@ -2,53 +2,145 @@ package errdetails
 
 import (
 	"context"
-	"errors"
+	"io"
+	"strings"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // assert UnaryServerInterceptor is of the same type UnaryServerInterceptor
 var _ grpc.UnaryServerInterceptor = UnaryServerInterceptor
 
 // UnaryServerInterceptor transcribes wrapped errors with details into gRPC Status.
+//
+// Errors are localized to the accept-language of the incoming metadata with
+// the package level Catalog, if one is set, and redacted by the package level
+// Redactor.
 func UnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 	resp, err = handler(ctx, req)
 
-	return resp, translateError(err)
+	return resp, translateError(ctx, err)
 }
 
 // assert StreamServerInterceptor is of the same type StreamServerInterceptor
 var _ grpc.StreamServerInterceptor = StreamServerInterceptor
 
 // StreamServerInterceptor transcribes wrapped errors with details into gRPC Status.
+//
+// Errors are localized to the accept-language of the incoming metadata with
+// the package level Catalog, if one is set, and redacted by the package level
+// Redactor.
 func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
-	return translateError(handler(srv, ss))
+	return translateError(ss.Context(), handler(srv, ss))
 }
 
-func translateError(err error) error {
-	// become a Status one way or another
-	var sterr statusError
-	if !errors.As(err, &sterr) {
-		sterr = &errCodeError{error: err, Code: codes.Unknown}
-	}
+func translateError(ctx context.Context, err error) error {
+	return redactor.Redact(ToStatus(Localize(err, incomingLocales(ctx)...))).Err()
+}
 
-	p := status.Convert(sterr).Proto()
-	for {
-		// turn error details into protobuf details
-		if msg, ok := err.(protoreflect.ProtoMessage); ok {
-			if any, err := anypb.New(msg); err == nil {
-				p.Details = append(p.Details, any)
-			}
-		}
-		// unwrap and move on the next
-		if err = errors.Unwrap(err); err == nil {
-			break
+// incomingLocales reads the locales preferred by the client from incoming
+// metadata, as set by the client itself or forwarded by grpc-gateway.
+func incomingLocales(ctx context.Context) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{"accept-language", "grpcgateway-accept-language"} {
+		if values := md.Get(key); len(values) > 0 {
+			return ParseAcceptLanguage(strings.Join(values, ","))
 		}
 	}
 
-	return status.FromProto(p).Err()
+	return nil
+}
+
+// assert UnaryClientInterceptor is of the same type UnaryClientInterceptor
+var _ grpc.UnaryClientInterceptor = UnaryClientInterceptor
+
+// UnaryClientInterceptor rebuilds wrapped errors with details from gRPC Status
+// returned by the server, such that errors.Is and errors.As are satisfied the
+// same as they would be on the server.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return rebuildError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// assert StreamClientInterceptor is of the same type StreamClientInterceptor
+var _ grpc.StreamClientInterceptor = StreamClientInterceptor
+
+// StreamClientInterceptor rebuilds wrapped errors with details from gRPC Status
+// returned by the server for the lifetime of a client stream.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, rebuildError(err)
+	}
+
+	return &clientStream{ClientStream: cs}, nil
+}
+
+// clientStream rebuilds errors returned from the wrapped ClientStream.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+// Header implements grpc.ClientStream interface.
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	return md, rebuildError(err)
+}
+
+// CloseSend implements grpc.ClientStream interface.
+func (s *clientStream) CloseSend() error {
+	return rebuildError(s.ClientStream.CloseSend())
+}
+
+// SendMsg implements grpc.ClientStream interface.
+func (s *clientStream) SendMsg(m interface{}) error {
+	return rebuildError(s.ClientStream.SendMsg(m))
+}
+
+// RecvMsg implements grpc.ClientStream interface.
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return rebuildError(s.ClientStream.RecvMsg(m))
+}
+
+func rebuildError(err error) error {
+	// io.EOF signals the end of a stream and must be returned as-is
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	// details that can't be unmarshaled leave the error as the client got it
+	rebuilt, uerr := fromStatus(s)
+	if uerr != nil {
+		handler.Handle(uerr)
+		return err
+	}
+
+	return &errRebuilt{error: rebuilt, status: s}
+}
+
+var _ statusError = (*errRebuilt)(nil)
+
+// errRebuilt is the outermost layer of an error rebuilt from a Status by the
+// client interceptors, such that the Status is found by status.FromError and
+// status.Code without unwrapping.
+type errRebuilt struct {
+	error
+	status *status.Status
+}
+
+// GRPCStatus implements interface required for status.FromError to turn the
+// error into a gRPC Status.
+func (e *errRebuilt) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// Unwrap implements errors.Unwrap interface.
+func (e *errRebuilt) Unwrap() error {
+	return e.error
 }
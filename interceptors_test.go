@@ -0,0 +1,148 @@
+package errdetails
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	testHandler(t)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return translateError(ctx, New(codes.NotFound, "no such user",
+			RetryDelay(time.Minute),
+			BadRequest(&errdetails.BadRequest_FieldViolation{
+				Field:       "user_id",
+				Description: "user does not exist",
+			}),
+		))
+	}
+
+	err := UnaryClientInterceptor(context.Background(), "/test.Service/Get", nil, nil, nil, invoker)
+
+	require.True(t, errors.Is(err, ErrNotFound), "expected error to be ErrNotFound")
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// the Status must be found without unwrapping, as older gRPC releases do not unwrap
+	sterr, ok := err.(interface{ GRPCStatus() *status.Status })
+	require.True(t, ok, "expected error to have GRPCStatus")
+	require.Equal(t, codes.NotFound, sterr.GRPCStatus().Code())
+	require.Len(t, sterr.GRPCStatus().Details(), 2)
+	require.Len(t, ToStatus(err).Details(), 2)
+
+	var badReq BadRequestError
+	require.True(t, errors.As(err, &badReq), "expected error to be BadRequestError")
+	require.Equal(t, "user_id", badReq.GetViolations()[0].GetField())
+
+	var retErr RetriableError
+	require.True(t, errors.As(err, &retErr), "expected error to be RetriableError")
+	require.Equal(t, time.Minute, retErr.GetRetryDelay())
+}
+
+func TestUnaryClientInterceptorUnknownDetail(t *testing.T) {
+	var handled []error
+	SetErrorHandler(errFunc(func(err error) {
+		handled = append(handled, err)
+	}))
+	t.Cleanup(func() { SetErrorHandler(nil) })
+
+	want := status.ErrorProto(&statuspb.Status{
+		Code:    int32(codes.NotFound),
+		Message: "no such user",
+		Details: []*anypb.Any{{TypeUrl: "type.googleapis.com/test.Unknown"}},
+	})
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return want
+	}
+
+	err := UnaryClientInterceptor(context.Background(), "/test.Service/Get", nil, nil, nil, invoker)
+	require.Equal(t, want, err)
+	require.Len(t, handled, 1)
+
+	require.Error(t, FromStatus(status.Convert(want)))
+	require.False(t, errors.Is(FromStatus(status.Convert(want)), ErrNotFound), "expected unmarshal error")
+}
+
+func TestUnaryClientInterceptorNoError(t *testing.T) {
+	testHandler(t)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+
+	require.NoError(t, UnaryClientInterceptor(context.Background(), "/test.Service/Get", nil, nil, nil, invoker))
+}
+
+type testClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s *testClientStream) RecvMsg(m interface{}) error {
+	return s.err
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	testHandler(t)
+
+	stream := &testClientStream{}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return stream, nil
+	}
+
+	cs, err := StreamClientInterceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/List", streamer)
+	require.NoError(t, err)
+
+	stream.err = io.EOF
+	require.Equal(t, io.EOF, cs.RecvMsg(nil))
+
+	stream.err = translateError(context.Background(), New(codes.FailedPrecondition, "terms of service not accepted",
+		PreconditionFailure(&errdetails.PreconditionFailure_Violation{
+			Type: "TOS",
+		}),
+	))
+	err = cs.RecvMsg(nil)
+
+	require.True(t, errors.Is(err, ErrFailedPrecondition), "expected error to be ErrFailedPrecondition")
+
+	var condErr FailedPreconditionError
+	require.True(t, errors.As(err, &condErr), "expected error to be FailedPreconditionError")
+	require.Equal(t, "TOS", condErr.GetViolations()[0].GetType())
+}
+
+func TestUnaryServerInterceptorLocalize(t *testing.T) {
+	testHandler(t)
+
+	SetCatalog(testCatalog{
+		"en": {"maintenance": "Down for maintenance."},
+		"fr": {"maintenance": "En maintenance."},
+	})
+	t.Cleanup(func() { SetCatalog(nil) })
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, New(codes.Unavailable, "service in maintenance mode", Localized("maintenance", nil))
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "fr-CA"))
+	_, err := UnaryServerInterceptor(ctx, nil, nil, handler)
+
+	require.Len(t, status.Convert(err).Details(), 1)
+	require.True(t, proto.Equal(&errdetails.LocalizedMessage{
+		Locale:  "fr",
+		Message: "En maintenance.",
+	}, status.Convert(err).Details()[0].(proto.Message)))
+}
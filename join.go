@@ -0,0 +1,105 @@
+package errdetails
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Join joins errors into a single error having the details of each, such that
+// errors.Is and errors.As are satisfied by any of the joined errors. Nil errors
+// are discarded, and Join returns nil if every error is nil.
+//
+// The joined error has the Status Code of the first joined error having one,
+// and the messages of every joined error separated by "; ".
+func Join(errs ...error) error {
+	e := &joinError{}
+	for _, err := range errs {
+		if err != nil {
+			e.errs = append(e.errs, err)
+		}
+	}
+	if len(e.errs) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+var _ statusError = (*joinError)(nil)
+
+type joinError struct {
+	errs []error
+}
+
+// Error implements error interface.
+func (e *joinError) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap implements Unwrap interface for errors joining multiple errors.
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
+
+// GRPCStatus implements interface required for status.FromError to turn the
+// error into a gRPC Status.
+func (e *joinError) GRPCStatus() *status.Status {
+	code := codes.Unknown
+	for _, err := range e.errs {
+		if sterr := findStatus(Classify(err)); sterr != nil {
+			code = status.Convert(sterr).Code()
+			break
+		}
+	}
+
+	return status.New(code, e.Error())
+}
+
+// StatusCode translates the gRPC Status Code to an equivilent HTTP status code.
+func (e *joinError) StatusCode() int {
+	return runtime.HTTPStatusFromCode(e.GRPCStatus().Code())
+}
+
+// walk calls fn for each error in the tree of a wrapped error, in depth-first
+// pre-order the same as errors.As, until fn returns false.
+//
+// Errors joining multiple errors are walked through an Unwrap() []error method,
+// and wrapped errors through errors.Unwrap.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if !fn(err) {
+			return false
+		}
+
+		if multi, ok := err.(multiError); ok {
+			for _, err := range multi.Unwrap() {
+				if !walk(err, fn) {
+					return false
+				}
+			}
+			return true
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return true
+}
+
+// findStatus finds the first error in the tree of a wrapped error having a
+// Status Code, or nil if there's none.
+func findStatus(err error) (found statusError) {
+	walk(err, func(err error) bool {
+		found, _ = err.(statusError)
+		return found == nil
+	})
+	return found
+}
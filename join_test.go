@@ -0,0 +1,110 @@
+package errdetails
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJoin(t *testing.T) {
+	testHandler(t)
+
+	err := Join(
+		nil,
+		New(codes.NotFound, "no such wallet", Resource(&errdetails.ResourceInfo{
+			ResourceType: "wallet",
+		})),
+		New(codes.InvalidArgument, "invalid transfer", BadRequest(&errdetails.BadRequest_FieldViolation{
+			Field: "amount",
+		})),
+	)
+	require.Equal(t, "no such wallet; invalid transfer", err.Error())
+	require.True(t, errors.Is(err, ErrNotFound), "expected error to be ErrNotFound")
+	require.True(t, errors.Is(err, ErrInvalidArgument), "expected error to be ErrInvalidArgument")
+
+	s := ToStatus(err)
+	require.Equal(t, codes.NotFound, s.Code())
+	require.Equal(t, "no such wallet; invalid transfer", s.Message())
+	require.Len(t, s.Details(), 2)
+	require.IsType(t, &errdetails.ResourceInfo{}, s.Details()[0])
+	require.IsType(t, &errdetails.BadRequest{}, s.Details()[1])
+
+	require.Nil(t, Join())
+	require.Nil(t, Join(nil, nil))
+}
+
+func TestJoinClassify(t *testing.T) {
+	testHandler(t)
+
+	s := ToStatus(Join(errors.New("oops"), context.Canceled))
+	require.Equal(t, codes.Canceled, s.Code())
+	require.Equal(t, "oops; context canceled", s.Message())
+
+	require.Equal(t, codes.Unknown, ToStatus(Join(errors.New("oops"))).Code())
+}
+
+func TestMultiErrorStatus(t *testing.T) {
+	testHandler(t)
+
+	err := WithHelp(testMultiError{
+		errors.New("oops"),
+		New(codes.FailedPrecondition, "wallet is frozen", PreconditionFailure(&errdetails.PreconditionFailure_Violation{
+			Type: "FROZEN",
+		})),
+		New(codes.Internal, "ledger unavailable", Help(&errdetails.Help_Link{
+			Url: "https://ledger.platform.test",
+		})),
+	}, &errdetails.Help_Link{Url: "https://wallet.platform.test"})
+
+	s := ToStatus(err)
+	require.Equal(t, codes.FailedPrecondition, s.Code())
+	require.Equal(t, "wallet is frozen", s.Message())
+	require.Len(t, s.Details(), 2)
+
+	help, ok := s.Details()[0].(*errdetails.Help)
+	require.True(t, ok, "expected first detail to be Help")
+	require.Len(t, help.GetLinks(), 2)
+	require.Equal(t, "https://wallet.platform.test", help.GetLinks()[0].GetUrl())
+	require.Equal(t, "https://ledger.platform.test", help.GetLinks()[1].GetUrl())
+	require.IsType(t, &errdetails.PreconditionFailure{}, s.Details()[1])
+}
+
+func TestJoinHandler(t *testing.T) {
+	testHandler(t)
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return Join(errors.New("oops"), New(codes.PermissionDenied, "wallet is not yours"))
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	err := CheckResponse(rr.Result())
+	require.True(t, errors.Is(err, ErrPermissionDenied), "expected error to be ErrPermissionDenied")
+	require.Equal(t, "oops; wallet is not yours", err.Error())
+}
+
+func TestJoinServerInterceptor(t *testing.T) {
+	testHandler(t)
+
+	_, err := UnaryServerInterceptor(context.Background(), nil, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, testMultiError{
+			context.DeadlineExceeded,
+			WithRetryDelay(errors.New("ledger unavailable"), 0),
+		}
+	})
+
+	s := status.Convert(err)
+	require.Equal(t, codes.DeadlineExceeded, s.Code())
+	require.Len(t, s.Details(), 1)
+	require.IsType(t, &errdetails.RetryInfo{}, s.Details()[0])
+}
@@ -0,0 +1,212 @@
+package errdetails
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ClaudiaJ/errdetails/details"
+)
+
+// Catalog is a source of messages translated to each of its locales.
+type Catalog interface {
+	// Locales lists the BCP 47 locales the catalog has messages for. The first
+	// locale is the default used when none requested by a client are available.
+	Locales() []string
+
+	// Message renders the message identified by id in a locale, reporting
+	// whether the catalog has such a message.
+	Message(locale, id string, args map[string]string) (string, bool)
+}
+
+// Localizer is implemented by errors able to render a message that is safe to
+// return to the user, in a locale negotiated with the client.
+type Localizer interface {
+	// Localize renders a localized message from the catalog.
+	Localize(locale string, catalog Catalog) (details.LocalizedMessage, bool)
+}
+
+type messageCatalog struct {
+	mu      sync.RWMutex
+	catalog Catalog
+}
+
+func (c *messageCatalog) get() Catalog {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.catalog
+}
+
+var (
+	// catalog may be used if set to localize errors before they're returned
+	// to the client.
+	catalog = &messageCatalog{}
+)
+
+// SetCatalog sets the package level message catalog used to localize errors
+// before they're returned to the client. Error responses of HandlerFunc then
+// vary by Accept-Language as well as Accept.
+//
+// By default, there is no catalog and errors are returned as they are.
+func SetCatalog(c Catalog) {
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+	catalog.catalog = c
+}
+
+// Localize wraps an error with a LocalizedMessage rendered by the first
+// Localizer in the error chain, in the catalog locale best matching the
+// locales preferred by the client.
+//
+// Should the message not be available in that locale, the next best match is
+// tried on down to the catalog default, e.g. "fr-CA" then "fr" then "en".
+//
+// The error is returned as-is if no catalog is set or no Localizer renders a
+// message.
+func Localize(err error, preferred ...string) error {
+	c := catalog.get()
+	if err == nil || c == nil {
+		return err
+	}
+
+	for _, locale := range matchLocales(preferred, c.Locales()) {
+		var (
+			msg   details.LocalizedMessage
+			found bool
+		)
+		walk(err, func(next error) bool {
+			switch l := next.(type) {
+			case *errLocalizable:
+				// metadata is resolved from the root, wherever Cause was applied
+				msg, found = l.localize(err, locale, c)
+			case Localizer:
+				msg, found = l.Localize(locale, c)
+			}
+			return !found
+		})
+
+		if found {
+			return WithLocalizedMessage(err, msg)
+		}
+	}
+
+	return err
+}
+
+// NegotiateLocale picks the available locale best matching the preferred
+// locales, given in order of preference.
+//
+// A preferred locale matches an available locale exactly, or otherwise falls
+// back to its less specific parent, e.g. "fr-CA" falls back to "fr". When no
+// preferred locale is available the first available locale is the default.
+func NegotiateLocale(preferred, available []string) string {
+	if locales := matchLocales(preferred, available); len(locales) > 0 {
+		return locales[0]
+	}
+	return ""
+}
+
+// matchLocales lists every available locale matching the preferred locales,
+// from the best match down to the default.
+func matchLocales(preferred, available []string) []string {
+	var matched []string
+	seen := make(map[string]bool, len(available))
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			matched = append(matched, locale)
+		}
+	}
+
+	for _, locale := range preferred {
+		for ; locale != ""; locale = parentLocale(locale) {
+			for _, candidate := range available {
+				if strings.EqualFold(locale, candidate) {
+					add(candidate)
+				}
+			}
+		}
+	}
+
+	if len(available) > 0 {
+		add(available[0])
+	}
+	return matched
+}
+
+// parentLocale truncates the last subtag from a locale, along with any single
+// character subtag left dangling, e.g. "zh-Hant-TW" becomes "zh-Hant".
+func parentLocale(locale string) string {
+	idx := strings.LastIndex(locale, "-")
+	for idx >= 2 && locale[idx-2] == '-' {
+		idx -= 2
+	}
+	if idx < 0 {
+		return ""
+	}
+	return locale[:idx]
+}
+
+// ParseAcceptLanguage parses the locales from an Accept-Language header in
+// order of preference, leaving out the wildcard.
+func ParseAcceptLanguage(header string) []string {
+	var locales []string
+	for _, locale := range parseQualityValues(header) {
+		if locale != "*" {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// parseQualityValues parses a header of comma separated values weighted by
+// quality, e.g. "fr-CA, fr;q=0.8, *;q=0.1", in order of preference.
+//
+// Values weighted zero are not acceptable, and are left out.
+func parseQualityValues(header string) []string {
+	var ordered []string
+	for _, v := range parseWeighted(header) {
+		if v.q > 0 {
+			ordered = append(ordered, v.value)
+		}
+	}
+	return ordered
+}
+
+// weighted is a value of a header having quality values.
+type weighted struct {
+	value string
+	q     float64
+}
+
+// parseWeighted parses the values of a header having quality values, like
+// Accept, ordered by quality and including those having a quality of zero.
+func parseWeighted(header string) []weighted {
+	var values []weighted
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		v := weighted{value: strings.TrimSpace(params[0]), q: 1}
+		if v.value == "" {
+			continue
+		}
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+				v.q = q
+			}
+		}
+
+		values = append(values, v)
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].q > values[j].q
+	})
+
+	return values
+}
@@ -0,0 +1,143 @@
+package errdetails
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/ClaudiaJ/errdetails/details"
+)
+
+type testCatalog map[string]map[string]string
+
+func (c testCatalog) Locales() []string {
+	return []string{"en", "fr", "fr-CA"}
+}
+
+func (c testCatalog) Message(locale, id string, _ map[string]string) (string, bool) {
+	msg, ok := c[locale][id]
+	return msg, ok
+}
+
+type testLocalizer struct {
+	error
+	id string
+}
+
+func (e *testLocalizer) Unwrap() error {
+	return e.error
+}
+
+func (e *testLocalizer) Localize(locale string, catalog Catalog) (details.LocalizedMessage, bool) {
+	msg, ok := catalog.Message(locale, e.id, nil)
+	return &errdetails.LocalizedMessage{Locale: locale, Message: msg}, ok
+}
+
+// testArgsCatalog interpolates arguments into its messages.
+type testArgsCatalog map[string]string
+
+func (c testArgsCatalog) Locales() []string {
+	return []string{"en"}
+}
+
+func (c testArgsCatalog) Message(_, id string, args map[string]string) (string, bool) {
+	msg, ok := c[id]
+	for k, v := range args {
+		msg = strings.ReplaceAll(msg, "{"+k+"}", v)
+	}
+	return msg, ok
+}
+
+func TestLocalizeMetadata(t *testing.T) {
+	testHandler(t)
+
+	SetCatalog(testArgsCatalog{"WALLET_EMPTY": "Your wallet has no more {product} available."})
+	t.Cleanup(func() { SetCatalog(nil) })
+
+	for name, err := range map[string]error{
+		"cause inside": New(codes.FailedPrecondition, "wallet is empty",
+			Cause(&errdetails.ErrorInfo{Reason: "WALLET_EMPTY", Metadata: map[string]string{"product": "credits"}}),
+			Localized("WALLET_EMPTY", nil),
+		),
+		"cause outside": New(codes.FailedPrecondition, "wallet is empty",
+			Localized("WALLET_EMPTY", nil),
+			Cause(&errdetails.ErrorInfo{Reason: "WALLET_EMPTY", Metadata: map[string]string{"product": "credits"}}),
+		),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var locErr LocalizedError
+			require.True(t, errors.As(Localize(err, "en"), &locErr), "expected error to be LocalizedError")
+			require.Equal(t, "Your wallet has no more credits available.", locErr.GetMessage())
+		})
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	available := []string{"en", "fr", "fr-CA", "zh-Hant"}
+
+	for _, tc := range []struct {
+		preferred []string
+		want      string
+	}{
+		{preferred: []string{"fr-CA"}, want: "fr-CA"},
+		{preferred: []string{"fr-ca"}, want: "fr-CA"},
+		{preferred: []string{"fr-BE"}, want: "fr"},
+		{preferred: []string{"zh-Hant-TW"}, want: "zh-Hant"},
+		{preferred: []string{"de", "fr"}, want: "fr"},
+		{preferred: []string{"de"}, want: "en"},
+		{preferred: nil, want: "en"},
+	} {
+		require.Equal(t, tc.want, NegotiateLocale(tc.preferred, available), "preferred %q", tc.preferred)
+	}
+
+	require.Equal(t, "", NegotiateLocale([]string{"fr"}, nil))
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	require.Equal(t,
+		[]string{"fr-CA", "en-US", "fr", "en"},
+		ParseAcceptLanguage("fr;q=0.8, en;q=0.5, fr-CA, de;q=0, *;q=0.1, en-US"),
+	)
+	require.Empty(t, ParseAcceptLanguage(""))
+}
+
+func TestHandlerLocalize(t *testing.T) {
+	testHandler(t)
+
+	SetCatalog(testCatalog{
+		"en": {"maintenance": "Down for maintenance."},
+		"fr": {"maintenance": "En maintenance."},
+	})
+	t.Cleanup(func() { SetCatalog(nil) })
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return &testLocalizer{
+			error: New(codes.Unavailable, "service in maintenance mode"),
+			id:    "maintenance",
+		}
+	})
+
+	for locale, want := range map[string]string{
+		"fr-CA,en;q=0.5": `{"@type": "type.googleapis.com/google.rpc.LocalizedMessage", "locale": "fr", "message": "En maintenance."}`,
+		"de":             `{"@type": "type.googleapis.com/google.rpc.LocalizedMessage", "locale": "en", "message": "Down for maintenance."}`,
+	} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", locale)
+
+		handler.ServeHTTP(rr, req)
+
+		require.JSONEq(t, `{
+			"code": 14,
+			"message": "service in maintenance mode",
+			"details": [`+want+`]
+		}`, rr.Body.String())
+		require.Equal(t, []string{"Accept", "Accept-Language"}, rr.Header().Values("Vary"))
+	}
+}
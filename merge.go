@@ -0,0 +1,142 @@
+package errdetails
+
+import (
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MergeFunc merges details of the same message type found in two layers of a
+// wrapped error into a single Status detail, the outer layer being the one
+// wrapping the other. Returning nil keeps both details.
+//
+// A MergeFunc must not modify either of the messages.
+type MergeFunc func(outer, inner proto.Message) proto.Message
+
+// Built-in MergeFuncs.
+var (
+	// MergeRepeated concatenates repeated fields of both details, those of the
+	// outer layer first, e.g. the FieldViolations of BadRequest details.
+	MergeRepeated MergeFunc = func(outer, inner proto.Message) proto.Message {
+		merged := proto.Clone(outer)
+		proto.Merge(merged, inner)
+		return merged
+	}
+
+	// MergeOutermost keeps the details of the outer layer.
+	MergeOutermost MergeFunc = func(outer, inner proto.Message) proto.Message {
+		return outer
+	}
+
+	// MergeInnermost keeps the details of the inner layer.
+	MergeInnermost MergeFunc = func(outer, inner proto.Message) proto.Message {
+		return inner
+	}
+
+	// MergeFields merges fields of both details, those set in the outer layer
+	// taking precedence, e.g. the Metadata of ErrorInfo details.
+	MergeFields MergeFunc = func(outer, inner proto.Message) proto.Message {
+		merged := proto.Clone(inner)
+		proto.Merge(merged, outer)
+		return merged
+	}
+
+	// MergeErrorInfo merges ErrorInfo details of the same domain and reason with
+	// MergeFields, keeping ErrorInfo details of different causes separate.
+	MergeErrorInfo MergeFunc = func(outer, inner proto.Message) proto.Message {
+		o, ook := outer.(*errdetails.ErrorInfo)
+		i, iok := inner.(*errdetails.ErrorInfo)
+		if !ook || !iok || o.GetDomain() != i.GetDomain() || o.GetReason() != i.GetReason() {
+			return nil
+		}
+
+		return MergeFields(outer, inner)
+	}
+
+	// MergeMaxRetryDelay keeps the RetryInfo details having the longest delay.
+	MergeMaxRetryDelay MergeFunc = func(outer, inner proto.Message) proto.Message {
+		o, ook := outer.(*errdetails.RetryInfo)
+		i, iok := inner.(*errdetails.RetryInfo)
+		if !ook || !iok {
+			return nil
+		}
+
+		if i.GetRetryDelay().AsDuration() > o.GetRetryDelay().AsDuration() {
+			return inner
+		}
+		return outer
+	}
+)
+
+// SetMergeFunc sets how ToStatus, and everything built on it, merges details
+// of a message type repeated in layers of a wrapped error. Setting a nil
+// MergeFunc keeps each layer as separate Status details.
+//
+// By default, repeatable details of BadRequest, Help, PreconditionFailure,
+// and QuotaFailure are merged with MergeRepeated, ErrorInfo with MergeErrorInfo,
+// and RetryInfo and RequestInfo with MergeOutermost. Details of any other
+// message type are kept separate.
+func SetMergeFunc(mt protoreflect.MessageType, fn MergeFunc) {
+	merger.mu.Lock()
+	defer merger.mu.Unlock()
+
+	name := mt.Descriptor().FullName()
+	if fn == nil {
+		delete(merger.funcs, name)
+		return
+	}
+	merger.funcs[name] = fn
+}
+
+type detailMerger struct {
+	mu    sync.RWMutex
+	funcs map[protoreflect.FullName]MergeFunc
+}
+
+// merge merges details of each message type having a MergeFunc, in order of
+// the outermost layer of each message type. A detail kept separate by the
+// MergeFunc may still be merged with the details of further layers.
+func (m *detailMerger) merge(msgs []proto.Message) []proto.Message {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	merged := make([]proto.Message, 0, len(msgs))
+	seen := make(map[protoreflect.FullName][]int, len(msgs))
+msgs:
+	for _, msg := range msgs {
+		name := msg.ProtoReflect().Descriptor().FullName()
+
+		if fn, ok := m.funcs[name]; ok {
+			for _, idx := range seen[name] {
+				if result := fn(merged[idx], msg); result != nil {
+					merged[idx] = result
+					continue msgs
+				}
+			}
+			seen[name] = append(seen[name], len(merged))
+		}
+
+		merged = append(merged, msg)
+	}
+
+	return merged
+}
+
+var (
+	// merger merges details repeated in layers of a wrapped error.
+	merger = &detailMerger{
+		funcs: map[protoreflect.FullName]MergeFunc{},
+	}
+)
+
+func init() {
+	SetMergeFunc((&errdetails.BadRequest{}).ProtoReflect().Type(), MergeRepeated)
+	SetMergeFunc((&errdetails.Help{}).ProtoReflect().Type(), MergeRepeated)
+	SetMergeFunc((&errdetails.PreconditionFailure{}).ProtoReflect().Type(), MergeRepeated)
+	SetMergeFunc((&errdetails.QuotaFailure{}).ProtoReflect().Type(), MergeRepeated)
+	SetMergeFunc((&errdetails.ErrorInfo{}).ProtoReflect().Type(), MergeErrorInfo)
+	SetMergeFunc((&errdetails.RetryInfo{}).ProtoReflect().Type(), MergeOutermost)
+	SetMergeFunc((&errdetails.RequestInfo{}).ProtoReflect().Type(), MergeOutermost)
+}
@@ -0,0 +1,106 @@
+package errdetails
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestMergeDetails(t *testing.T) {
+	testHandler(t)
+
+	err := New(codes.InvalidArgument, "invalid wallet",
+		BadRequest(&errdetails.BadRequest_FieldViolation{
+			Field:       "wallet.owner",
+			Description: "must be set",
+		}),
+		Cause(&errdetails.ErrorInfo{
+			Reason:   "INVALID_OWNER",
+			Domain:   "wallet.platform.test",
+			Metadata: map[string]string{"owner": "", "wallet": "123"},
+		}),
+		RetryDelay(time.Second),
+	)
+	err = WithDetails(err,
+		BadRequest(&errdetails.BadRequest_FieldViolation{
+			Field:       "wallet.balance",
+			Description: "must not be negative",
+		}),
+		Cause(&errdetails.ErrorInfo{
+			Reason:   "INVALID_WALLET",
+			Metadata: map[string]string{"owner": "auth0|123"},
+		}),
+		RetryDelay(time.Millisecond),
+	)
+
+	err = WithDetails(err,
+		Cause(&errdetails.ErrorInfo{
+			Reason:   "INVALID_OWNER",
+			Domain:   "wallet.platform.test",
+			Metadata: map[string]string{"owner": "auth0|456"},
+		}),
+	)
+
+	details := ToStatus(err).Details()
+	require.Len(t, details, 4)
+	require.True(t, proto.Equal(&errdetails.ErrorInfo{
+		Reason:   "INVALID_OWNER",
+		Domain:   "wallet.platform.test",
+		Metadata: map[string]string{"owner": "auth0|456", "wallet": "123"},
+	}, details[0].(proto.Message)), "expected merged ErrorInfo of the same reason")
+	require.True(t, proto.Equal(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Millisecond),
+	}, details[1].(proto.Message)), "expected outermost RetryInfo")
+	require.True(t, proto.Equal(&errdetails.ErrorInfo{
+		Reason:   "INVALID_WALLET",
+		Metadata: map[string]string{"owner": "auth0|123"},
+	}, details[2].(proto.Message)), "expected ErrorInfo of another reason kept separate")
+	require.True(t, proto.Equal(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       "wallet.balance",
+			Description: "must not be negative",
+		}, {
+			Field:       "wallet.owner",
+			Description: "must be set",
+		}},
+	}, details[3].(proto.Message)), "expected concatenated BadRequest")
+
+	// layers themselves are left as they were
+	var badErr BadRequestError
+	require.True(t, errors.As(err, &badErr), "expected error to be BadRequestError")
+	require.Len(t, badErr.GetViolations(), 1)
+
+	retryType := (&errdetails.RetryInfo{}).ProtoReflect().Type()
+	t.Cleanup(func() { SetMergeFunc(retryType, MergeOutermost) })
+
+	SetMergeFunc(retryType, MergeMaxRetryDelay)
+	details = ToStatus(err).Details()
+	require.Len(t, details, 4)
+	require.True(t, proto.Equal(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Second),
+	}, details[1].(proto.Message)), "expected longest RetryInfo")
+
+	SetMergeFunc(retryType, nil)
+	require.Len(t, ToStatus(err).Details(), 5)
+}
+
+func TestMergeReasons(t *testing.T) {
+	testHandler(t)
+
+	err := errWalletFrozen.Wrap(errWalletEmpty.New("no more Redlines available"))
+	require.Len(t, ToStatus(err).Details(), 2)
+
+	b, jerr := ToJSON(err)
+	require.NoError(t, jerr)
+
+	rebuilt := FromJSON(bytes.NewReader(b))
+	require.True(t, errors.Is(rebuilt, errWalletFrozen), "expected rebuilt error to be errWalletFrozen")
+	require.True(t, errors.Is(rebuilt, errWalletEmpty), "expected rebuilt error to be errWalletEmpty")
+}
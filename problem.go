@@ -0,0 +1,334 @@
+package errdetails
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemMembers are the errdetails messages encoded as extension members of a
+// problem details document, by member name.
+var problemMembers = []struct {
+	name string
+	msg  proto.Message
+}{
+	{"errorInfo", &errdetails.ErrorInfo{}},
+	{"retryInfo", &errdetails.RetryInfo{}},
+	{"debugInfo", &errdetails.DebugInfo{}},
+	{"quotaFailure", &errdetails.QuotaFailure{}},
+	{"preconditionFailure", &errdetails.PreconditionFailure{}},
+	{"badRequest", &errdetails.BadRequest{}},
+	{"requestInfo", &errdetails.RequestInfo{}},
+	{"resourceInfo", &errdetails.ResourceInfo{}},
+	{"help", &errdetails.Help{}},
+	{"localizedMessage", &errdetails.LocalizedMessage{}},
+}
+
+// ToProblemJSON writes an error as an RFC 9457 problem details JSON document
+// such that it can be mostly recovered with FromProblemJSON, less anything
+// redacted by the package level Redactor.
+//
+// The Status maps to problem details members as follows:
+//
+//   - "type" is "about:blank", unless derived from the ErrorInfo details by the
+//     package level ProblemTypeFunc, see SetProblemTypeFunc
+//   - "title" is the name of the Status Code, e.g. "NotFound"
+//   - "status" is the HTTP status code equivalent to the Status Code
+//   - "detail" is the Status message
+//   - "instance" is the request ID of the RequestInfo details, if any
+//
+// Each errdetails detail is an extension member named for its message type,
+// e.g. "badRequest". Details of any other message type, or repeating a message
+// type, are listed in the "details" extension member the same as in JSON.
+// Extension members are in the order of the details, such that FromProblemJSON
+// rebuilds the details in the same order.
+func ToProblemJSON(from error) ([]byte, error) {
+	return ProblemEncoder.Encode(redactor.Redact(ToStatus(from)))
+}
+
+// FromProblemJSON reads an RFC 9457 problem details JSON document from a
+// Reader like a response Body, and makes best effort to reconstruct the
+// wrapped error the same as FromJSON.
+//
+// Members of problem details documents from other sources may share the name
+// of a detail without describing one. Members and details that fail to decode
+// are skipped, rather than failing the document.
+func FromProblemJSON(r io.Reader) error {
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return err
+	}
+
+	members, err := problemDocument(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	var problem struct {
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &problem); err != nil {
+		return err
+	}
+
+	code, ok := codeFromName(problem.Title)
+	if !ok {
+		code = codeFromHTTPStatus(problem.Status)
+	}
+	p := &statuspb.Status{Code: int32(code), Message: problem.Detail}
+
+	msgs := make(map[string]proto.Message, len(problemMembers))
+	for _, member := range problemMembers {
+		msgs[member.name] = member.msg
+	}
+
+	// details are rebuilt in the order of the document members
+	hasRequestInfo := false
+	for _, member := range members {
+		if member.name == "details" {
+			var details []json.RawMessage
+			if err := json.Unmarshal(member.value, &details); err != nil {
+				continue
+			}
+
+			for _, detail := range details {
+				any := &anypb.Any{}
+				if err := protojson.Unmarshal(detail, any); err != nil {
+					continue
+				}
+				p.Details = append(p.Details, any)
+			}
+			continue
+		}
+
+		mt, ok := msgs[member.name]
+		if !ok {
+			continue
+		}
+
+		msg := mt.ProtoReflect().New().Interface()
+		if err := protojson.Unmarshal(member.value, msg); err != nil {
+			continue
+		}
+		hasRequestInfo = hasRequestInfo || member.name == "requestInfo"
+
+		any, err := anypb.New(msg)
+		if err != nil {
+			return err
+		}
+		p.Details = append(p.Details, any)
+	}
+
+	if !hasRequestInfo && problem.Instance != "" {
+		any, err := anypb.New(&errdetails.RequestInfo{RequestId: problem.Instance})
+		if err != nil {
+			return err
+		}
+		p.Details = append(p.Details, any)
+	}
+
+	return FromStatus(status.FromProto(p))
+}
+
+type problemEncoder struct{}
+
+// ContentType implements Encoder interface.
+func (problemEncoder) ContentType() string {
+	return problemContentType
+}
+
+// Encode implements Encoder interface.
+func (problemEncoder) Encode(s *status.Status) ([]byte, error) {
+	problemType, instance := "about:blank", ""
+
+	names := make(map[protoreflect.FullName]string, len(problemMembers))
+	for _, member := range problemMembers {
+		names[member.msg.ProtoReflect().Descriptor().FullName()] = member.name
+	}
+
+	// extension members are in the order of the details, with the "details"
+	// member in place of the first detail it lists
+	var members []problemMember
+	var details []json.RawMessage
+	seen := map[string]bool{}
+	for _, detail := range s.Proto().GetDetails() {
+		name, ok := names[detail.MessageName()]
+		if !ok || seen[name] {
+			b, err := protojson.Marshal(detail)
+			if err != nil {
+				return nil, err
+			}
+			if !seen["details"] {
+				seen["details"] = true
+				members = append(members, problemMember{name: "details"})
+			}
+			details = append(details, b)
+			continue
+		}
+		seen[name] = true
+
+		msg, err := detail.UnmarshalNew()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, problemMember{name: name, value: b})
+
+		switch msg := msg.(type) {
+		case *errdetails.ErrorInfo:
+			if t := problemTypes.get()(msg.GetDomain(), msg.GetReason()); t != "" {
+				problemType = t
+			}
+		case *errdetails.RequestInfo:
+			instance = msg.GetRequestId()
+		}
+	}
+
+	for idx, member := range members {
+		if member.name == "details" {
+			b, err := json.Marshal(details)
+			if err != nil {
+				return nil, err
+			}
+			members[idx].value = b
+		}
+	}
+
+	header := []problemMember{
+		{"type", jsonString(problemType)},
+		{"title", jsonString(s.Code().String())},
+		{"status", json.RawMessage(strconv.Itoa(runtime.HTTPStatusFromCode(s.Code())))},
+	}
+	if s.Message() != "" {
+		header = append(header, problemMember{"detail", jsonString(s.Message())})
+	}
+	if instance != "" {
+		header = append(header, problemMember{"instance", jsonString(instance)})
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for idx, member := range append(header, members...) {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(jsonString(member.name))
+		buf.WriteByte(':')
+		buf.Write(member.value)
+	}
+	buf.WriteString("}\n")
+
+	return buf.Bytes(), nil
+}
+
+// problemMember is a member of a problem details document.
+type problemMember struct {
+	name  string
+	value json.RawMessage
+}
+
+// problemDocument decodes the members of a problem details document in the
+// order they appear.
+func problemDocument(b []byte) ([]problemMember, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("problem details document is not a JSON object")
+	}
+
+	var members []problemMember
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		member := problemMember{name: tok.(string)}
+		if err := dec.Decode(&member.value); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+func jsonString(v string) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+// ProblemTypeFunc derives the "type" URI of a problem details document from
+// the domain and reason of its ErrorInfo details, or returns "" to leave it
+// "about:blank".
+type ProblemTypeFunc func(domain, reason string) string
+
+type problemTyper struct {
+	mu sync.RWMutex
+	fn ProblemTypeFunc
+}
+
+func (t *problemTyper) get() ProblemTypeFunc {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.fn
+}
+
+var (
+	// problemTypes derives the "type" URI of problem details documents.
+	problemTypes = &problemTyper{fn: func(_, _ string) string { return "" }}
+)
+
+// SetProblemTypeFunc sets the package level ProblemTypeFunc deriving the
+// "type" URI of problem details documents, for services publishing a document
+// describing each reason at a resolvable URI, e.g.
+//
+//	errdetails.SetProblemTypeFunc(func(domain, reason string) string {
+//		return "https://" + domain + "/problems/" + reason
+//	})
+//
+// By default, or when set to nil, the "type" is "about:blank" as recommended
+// by RFC 9457 when there's no such document.
+func SetProblemTypeFunc(fn ProblemTypeFunc) {
+	if fn == nil {
+		fn = func(_, _ string) string { return "" }
+	}
+
+	problemTypes.mu.Lock()
+	defer problemTypes.mu.Unlock()
+	problemTypes.fn = fn
+}
+
+// codeFromName looks up a Status Code by its name, e.g. "NotFound".
+func codeFromName(name string) (codes.Code, bool) {
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == name {
+			return code, true
+		}
+	}
+
+	return codes.Unknown, false
+}
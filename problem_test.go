@@ -0,0 +1,166 @@
+package errdetails
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProblemEncoder(t *testing.T) {
+	testHandler(t)
+
+	SetEncoder(ProblemEncoder)
+	t.Cleanup(func() { SetEncoder(nil) })
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return New(codes.ResourceExhausted, "no more Redlines available",
+			Cause(&errdetails.ErrorInfo{
+				Reason: "WALLET_EMPTY",
+				Domain: "wallet.platform.test",
+			}),
+			RequestInfo(&errdetails.RequestInfo{
+				RequestId: "123456789",
+			}),
+			wrapperFunc(func(err error) error {
+				return &arbitraryError{error: err, ProtoMessage: wrapperspb.String("arbitrary")}
+			}),
+		)
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	require.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "ResourceExhausted",
+		"status": 429,
+		"detail": "no more Redlines available",
+		"instance": "123456789",
+		"errorInfo": {
+			"reason": "WALLET_EMPTY",
+			"domain": "wallet.platform.test"
+		},
+		"requestInfo": {
+			"requestId": "123456789"
+		},
+		"details": [{
+			"@type": "type.googleapis.com/google.protobuf.StringValue",
+			"value": "arbitrary"
+		}]
+	}`, rr.Body.String())
+
+	err := FromProblemJSON(rr.Body)
+
+	require.True(t, errors.Is(err, ErrResourceExhausted), "expected error to be ErrResourceExhausted")
+
+	var causedErr CausedError
+	require.True(t, errors.As(err, &causedErr), "expected error to be CausedError")
+	require.Equal(t, "WALLET_EMPTY", causedErr.GetReason())
+
+	var reqErr RequestInfoError
+	require.True(t, errors.As(err, &reqErr), "expected error to be RequestInfoError")
+	require.Equal(t, "123456789", reqErr.GetRequestId())
+}
+
+func TestProblemType(t *testing.T) {
+	testHandler(t)
+
+	SetProblemTypeFunc(func(domain, reason string) string {
+		return "https://" + domain + "/problems/" + reason
+	})
+	t.Cleanup(func() { SetProblemTypeFunc(nil) })
+
+	b, err := ToProblemJSON(New(codes.ResourceExhausted, "no more Redlines available",
+		Cause(&errdetails.ErrorInfo{
+			Reason: "WALLET_EMPTY",
+			Domain: "wallet.platform.test",
+		}),
+	))
+	require.NoError(t, err)
+
+	var doc struct {
+		Type string `json:"type"`
+	}
+	require.NoError(t, json.Unmarshal(b, &doc))
+	require.Equal(t, "https://wallet.platform.test/problems/WALLET_EMPTY", doc.Type)
+}
+
+func TestProblemJSONRoundTrip(t *testing.T) {
+	testHandler(t)
+
+	want := New(codes.FailedPrecondition, "wallet is frozen",
+		Cause(&errdetails.ErrorInfo{Reason: "WALLET_FROZEN", Domain: "wallet.platform.test"}),
+		wrapperFunc(func(err error) error {
+			return &arbitraryError{error: err, ProtoMessage: wrapperspb.String("arbitrary")}
+		}),
+		PreconditionFailure(&errdetails.PreconditionFailure_Violation{Type: "FROZEN"}),
+		Help(&errdetails.Help_Link{Url: "https://wallet.platform.test/docs/frozen"}),
+	)
+
+	b, err := ToProblemJSON(want)
+	require.NoError(t, err)
+
+	members, err := problemDocument(b)
+	require.NoError(t, err)
+
+	var names []string
+	for _, member := range members {
+		names = append(names, member.name)
+	}
+	require.Equal(t, []string{"type", "title", "status", "detail", "help", "preconditionFailure", "details", "errorInfo"}, names)
+
+	err = FromProblemJSON(bytes.NewReader(b))
+	require.True(t, proto.Equal(ToStatus(want).Proto(), ToStatus(err).Proto()), "expected details in the same order")
+}
+
+func TestFromProblemJSON(t *testing.T) {
+	testHandler(t)
+
+	err := FromProblemJSON(strings.NewReader(`{
+		"type": "https://example.test/probs/out-of-credit",
+		"title": "You do not have enough credit.",
+		"status": 403,
+		"detail": "Your current balance is 30, but that costs 50.",
+		"instance": "/account/12345/msgs/abc"
+	}`))
+
+	require.True(t, errors.Is(err, ErrPermissionDenied), "expected error to be ErrPermissionDenied")
+	require.Equal(t, "Your current balance is 30, but that costs 50.", err.Error())
+
+	var reqErr RequestInfoError
+	require.True(t, errors.As(err, &reqErr), "expected error to be RequestInfoError")
+	require.Equal(t, "/account/12345/msgs/abc", reqErr.GetRequestId())
+}
+
+func TestFromProblemJSONForeignMembers(t *testing.T) {
+	testHandler(t)
+
+	err := FromProblemJSON(strings.NewReader(`{
+		"title": "Not Found",
+		"status": 404,
+		"detail": "no such wallet",
+		"help": "see docs",
+		"requestInfo": 123,
+		"details": [{"@type": "type.googleapis.com/unknown.Detail"}, "oops"],
+		"instance": "/wallets/123"
+	}`))
+
+	require.True(t, errors.Is(err, ErrNotFound), "expected error to be ErrNotFound")
+	require.Equal(t, "no such wallet", err.Error())
+
+	var reqErr RequestInfoError
+	require.True(t, errors.As(err, &reqErr), "expected error to be RequestInfoError")
+	require.Equal(t, "/wallets/123", reqErr.GetRequestId())
+}
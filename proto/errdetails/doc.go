@@ -0,0 +1,11 @@
+// Package errdetailspb is the Go package of errdetails/options.proto, the
+// options read by protoc-gen-go-errdetails. Go code generated by protoc-gen-go
+// for proto files importing the options imports this package.
+//
+// It is generated by protoc-gen-go with the proto directory of this module and
+// a checkout of https://github.com/googleapis/googleapis as import paths:
+//
+//	GOOGLEAPIS=path/to/googleapis go generate ./proto/errdetails
+package errdetailspb
+
+//go:generate protoc -I .. -I ${GOOGLEAPIS} --go_out=../.. --go_opt=module=github.com/ClaudiaJ/errdetails ../errdetails/options.proto
@@ -0,0 +1,295 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: errdetails/options.proto
+
+package errdetailspb
+
+import (
+	code "google.golang.org/genproto/googleapis/rpc/code"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ReasonsOptions are the options of an ErrorReason enum.
+type ReasonsOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Domain of the ErrorInfo of each reason, defaulting to the proto package.
+	Domain string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Code of errors having any reason not having a code of its own, defaulting
+	// to UNKNOWN.
+	DefaultCode code.Code `protobuf:"varint,2,opt,name=default_code,json=defaultCode,proto3,enum=google.rpc.Code" json:"default_code,omitempty"`
+}
+
+func (x *ReasonsOptions) Reset() {
+	*x = ReasonsOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_errdetails_options_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReasonsOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReasonsOptions) ProtoMessage() {}
+
+func (x *ReasonsOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_errdetails_options_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReasonsOptions.ProtoReflect.Descriptor instead.
+func (*ReasonsOptions) Descriptor() ([]byte, []int) {
+	return file_errdetails_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReasonsOptions) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ReasonsOptions) GetDefaultCode() code.Code {
+	if x != nil {
+		return x.DefaultCode
+	}
+	return code.Code(0)
+}
+
+// ReasonOptions are the options of a reason of an ErrorReason enum.
+type ReasonOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Code of errors having the reason.
+	Code code.Code `protobuf:"varint,1,opt,name=code,proto3,enum=google.rpc.Code" json:"code,omitempty"`
+	// Message of errors having the reason, defaulting to the reason itself.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ReasonOptions) Reset() {
+	*x = ReasonOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_errdetails_options_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReasonOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReasonOptions) ProtoMessage() {}
+
+func (x *ReasonOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_errdetails_options_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReasonOptions.ProtoReflect.Descriptor instead.
+func (*ReasonOptions) Descriptor() ([]byte, []int) {
+	return file_errdetails_options_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReasonOptions) GetCode() code.Code {
+	if x != nil {
+		return x.Code
+	}
+	return code.Code(0)
+}
+
+func (x *ReasonOptions) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var file_errdetails_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.EnumOptions)(nil),
+		ExtensionType: (*ReasonsOptions)(nil),
+		Field:         52100,
+		Name:          "errdetails.reasons",
+		Tag:           "bytes,52100,opt,name=reasons",
+		Filename:      "errdetails/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
+		ExtensionType: (*ReasonOptions)(nil),
+		Field:         52100,
+		Name:          "errdetails.reason",
+		Tag:           "bytes,52100,opt,name=reason",
+		Filename:      "errdetails/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.EnumOptions.
+var (
+	// optional errdetails.ReasonsOptions reasons = 52100;
+	E_Reasons = &file_errdetails_options_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.EnumValueOptions.
+var (
+	// optional errdetails.ReasonOptions reason = 52100;
+	E_Reason = &file_errdetails_options_proto_extTypes[1]
+)
+
+var File_errdetails_options_proto protoreflect.FileDescriptor
+
+var file_errdetails_options_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x65, 0x72, 0x72, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x2f, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x65, 0x72, 0x72, 0x64,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x5d, 0x0a, 0x0e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x33, 0x0a, 0x0c, 0x64, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x10, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x4f,
+	0x0a, 0x0d, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x24, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x3a,
+	0x54, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6e, 0x75,
+	0x6d, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x84, 0x97, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x65, 0x72, 0x72, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x2e, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x73, 0x3a, 0x56, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12,
+	0x21, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x84, 0x97, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x65, 0x72, 0x72,
+	0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x42, 0x3e, 0x5a,
+	0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x43, 0x6c, 0x61, 0x75,
+	0x64, 0x69, 0x61, 0x4a, 0x2f, 0x65, 0x72, 0x72, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x72, 0x72, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x3b, 0x65, 0x72, 0x72, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_errdetails_options_proto_rawDescOnce sync.Once
+	file_errdetails_options_proto_rawDescData = file_errdetails_options_proto_rawDesc
+)
+
+func file_errdetails_options_proto_rawDescGZIP() []byte {
+	file_errdetails_options_proto_rawDescOnce.Do(func() {
+		file_errdetails_options_proto_rawDescData = protoimpl.X.CompressGZIP(file_errdetails_options_proto_rawDescData)
+	})
+	return file_errdetails_options_proto_rawDescData
+}
+
+var file_errdetails_options_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_errdetails_options_proto_goTypes = []interface{}{
+	(*ReasonsOptions)(nil),                // 0: errdetails.ReasonsOptions
+	(*ReasonOptions)(nil),                 // 1: errdetails.ReasonOptions
+	(code.Code)(0),                        // 2: google.rpc.Code
+	(*descriptorpb.EnumOptions)(nil),      // 3: google.protobuf.EnumOptions
+	(*descriptorpb.EnumValueOptions)(nil), // 4: google.protobuf.EnumValueOptions
+}
+var file_errdetails_options_proto_depIdxs = []int32{
+	2, // 0: errdetails.ReasonsOptions.default_code:type_name -> google.rpc.Code
+	2, // 1: errdetails.ReasonOptions.code:type_name -> google.rpc.Code
+	3, // 2: errdetails.reasons:extendee -> google.protobuf.EnumOptions
+	4, // 3: errdetails.reason:extendee -> google.protobuf.EnumValueOptions
+	0, // 4: errdetails.reasons:type_name -> errdetails.ReasonsOptions
+	1, // 5: errdetails.reason:type_name -> errdetails.ReasonOptions
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	4, // [4:6] is the sub-list for extension type_name
+	2, // [2:4] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_errdetails_options_proto_init() }
+func file_errdetails_options_proto_init() {
+	if File_errdetails_options_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_errdetails_options_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReasonsOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_errdetails_options_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReasonOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_errdetails_options_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_errdetails_options_proto_goTypes,
+		DependencyIndexes: file_errdetails_options_proto_depIdxs,
+		MessageInfos:      file_errdetails_options_proto_msgTypes,
+		ExtensionInfos:    file_errdetails_options_proto_extTypes,
+	}.Build()
+	File_errdetails_options_proto = out.File
+	file_errdetails_options_proto_rawDesc = nil
+	file_errdetails_options_proto_goTypes = nil
+	file_errdetails_options_proto_depIdxs = nil
+}
@@ -0,0 +1,138 @@
+package errdetails
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClaudiaJ/errdetails/details"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimit describes a quota policy of the IETF RateLimit and
+// RateLimit-Policy response headers.
+type RateLimit struct {
+	// Policy names the quota policy.
+	Policy string
+
+	// Quota is the quota units allowed within the Window. The RateLimit-Policy
+	// header is only written for policies having a Quota.
+	Quota int64
+
+	// Window is the time window the Quota applies to.
+	Window time.Duration
+
+	// Remaining is the quota units left in the current Window.
+	Remaining int64
+
+	// Reset is the time until the quota is restored.
+	Reset time.Duration
+}
+
+// RateLimitMapper derives a RateLimit from a violation of QuotaFailure details
+// and the retry delay of the error, if any.
+type RateLimitMapper interface {
+	RateLimit(violation details.QuotaViolation, retryDelay time.Duration) (RateLimit, bool)
+}
+
+// RateLimitMapperFunc type is an adapter to allow the use of ordinary functions
+// as RateLimitMapper.
+type RateLimitMapperFunc func(violation details.QuotaViolation, retryDelay time.Duration) (RateLimit, bool)
+
+// RateLimit calls fn(violation, retryDelay).
+func (fn RateLimitMapperFunc) RateLimit(violation details.QuotaViolation, retryDelay time.Duration) (RateLimit, bool) {
+	return fn(violation, retryDelay)
+}
+
+// DefaultRateLimitMapper describes each quota violation as a policy named by
+// its subject, having no quota remaining until the retry delay has passed.
+var DefaultRateLimitMapper RateLimitMapper = RateLimitMapperFunc(func(violation details.QuotaViolation, retryDelay time.Duration) (RateLimit, bool) {
+	return RateLimit{
+		Policy: violation.GetSubject(),
+		Reset:  retryDelay,
+	}, violation.GetSubject() != ""
+})
+
+type rateLimitMapper struct {
+	mu     sync.RWMutex
+	mapper RateLimitMapper
+}
+
+func (m *rateLimitMapper) get() RateLimitMapper {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mapper
+}
+
+var (
+	// rateLimits derives RateLimit headers of error responses.
+	rateLimits = &rateLimitMapper{mapper: DefaultRateLimitMapper}
+)
+
+// SetRateLimitMapper sets the package level RateLimitMapper used by HandlerFunc
+// to derive the RateLimit and RateLimit-Policy headers of error responses from
+// QuotaFailure details.
+//
+// By default, or when set to nil, the DefaultRateLimitMapper is used.
+func SetRateLimitMapper(m RateLimitMapper) {
+	if m == nil {
+		m = DefaultRateLimitMapper
+	}
+
+	rateLimits.mu.Lock()
+	defer rateLimits.mu.Unlock()
+	rateLimits.mapper = m
+}
+
+// setRetryHeaders sets the Retry-After header from the RetryInfo details of a
+// Status, and the RateLimit headers from its QuotaFailure details.
+func setRetryHeaders(h http.Header, s *status.Status) {
+	var retryDelay time.Duration
+	var violations []*errdetails.QuotaFailure_Violation
+	for _, detail := range s.Details() {
+		switch d := detail.(type) {
+		case *errdetails.RetryInfo:
+			if delay := d.GetRetryDelay().AsDuration(); delay > retryDelay {
+				retryDelay = delay
+			}
+		case *errdetails.QuotaFailure:
+			violations = append(violations, d.GetViolations()...)
+		}
+	}
+
+	if retryDelay > 0 {
+		h.Set("Retry-After", strconv.FormatInt(seconds(retryDelay), 10))
+	}
+
+	mapper := rateLimits.get()
+
+	var limits, policies []string
+	for _, violation := range violations {
+		rl, ok := mapper.RateLimit(violation, retryDelay)
+		if !ok {
+			continue
+		}
+
+		name := strconv.Quote(rl.Policy)
+		limits = append(limits, name+";r="+strconv.FormatInt(rl.Remaining, 10)+";t="+strconv.FormatInt(seconds(rl.Reset), 10))
+		if rl.Quota > 0 {
+			policies = append(policies, name+";q="+strconv.FormatInt(rl.Quota, 10)+";w="+strconv.FormatInt(seconds(rl.Window), 10))
+		}
+	}
+
+	if len(limits) > 0 {
+		h.Set("RateLimit", strings.Join(limits, ", "))
+	}
+	if len(policies) > 0 {
+		h.Set("RateLimit-Policy", strings.Join(policies, ", "))
+	}
+}
+
+// seconds rounds a duration up to whole seconds.
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
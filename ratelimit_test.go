@@ -0,0 +1,84 @@
+package errdetails
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ClaudiaJ/errdetails/details"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var rateLimitedHandler = HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+	return New(codes.ResourceExhausted, "resend email rate limit exceeded",
+		RetryDelay(1500*time.Millisecond),
+		QuotaFailure(&errdetails.QuotaFailure_Violation{
+			Subject:     "resend-email",
+			Description: "Rate limit applied for Resend Email",
+		}),
+	)
+})
+
+func TestRetryHeaders(t *testing.T) {
+	testHandler(t)
+
+	rr := httptest.NewRecorder()
+	rateLimitedHandler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	require.Equal(t, "2", rr.Header().Get("Retry-After"))
+	require.Equal(t, `"resend-email";r=0;t=2`, rr.Header().Get("RateLimit"))
+	require.Empty(t, rr.Header().Get("RateLimit-Policy"))
+}
+
+func TestSetRateLimitMapper(t *testing.T) {
+	testHandler(t)
+
+	SetRateLimitMapper(RateLimitMapperFunc(func(violation details.QuotaViolation, retryDelay time.Duration) (RateLimit, bool) {
+		return RateLimit{
+			Policy: violation.GetSubject(),
+			Quota:  5,
+			Window: time.Hour,
+			Reset:  retryDelay,
+		}, true
+	}))
+	t.Cleanup(func() { SetRateLimitMapper(nil) })
+
+	rr := httptest.NewRecorder()
+	rateLimitedHandler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	require.Equal(t, `"resend-email";r=0;t=2`, rr.Header().Get("RateLimit"))
+	require.Equal(t, `"resend-email";q=5;w=3600`, rr.Header().Get("RateLimit-Policy"))
+}
+
+func TestRetryHeadersRedacted(t *testing.T) {
+	testHandler(t)
+
+	SetRedactor(DropDetails(&errdetails.RetryInfo{}, &errdetails.QuotaFailure{}))
+	t.Cleanup(func() { SetRedactor(nil) })
+
+	rr := httptest.NewRecorder()
+	rateLimitedHandler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	require.Empty(t, rr.Header().Get("Retry-After"))
+	require.Empty(t, rr.Header().Get("RateLimit"))
+}
+
+func TestCheckResponseRetryAfter(t *testing.T) {
+	testHandler(t)
+
+	rr := httptest.NewRecorder()
+	rr.Header().Set("Retry-After", "30")
+	rr.WriteHeader(http.StatusServiceUnavailable)
+
+	err := CheckResponse(rr.Result())
+	require.True(t, errors.Is(err, ErrUnavailable), "expected error to be ErrUnavailable")
+
+	var retErr RetriableError
+	require.True(t, errors.As(err, &retErr), "expected error to be RetriableError")
+	require.Equal(t, 30*time.Second, retErr.GetRetryDelay())
+}
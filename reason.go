@@ -0,0 +1,83 @@
+package errdetails
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// ReasonError is a sentinel error for a precise reason of failure within a
+// domain, for use as target of errors.Is().
+//
+// A ReasonError matches any error having CausedError details of the same
+// domain and reason, including errors reconstructed by FromStatus on the other
+// side of a service boundary.
+//
+//	var ErrWalletEmpty = errdetails.Reason("wallet.platform.test", "WALLET_EMPTY", codes.ResourceExhausted)
+//
+//	return ErrWalletEmpty.New("no more Redlines available")
+//	...
+//	if errors.Is(err, ErrWalletEmpty) {
+type ReasonError struct {
+	domain string
+	reason string
+	code   codes.Code
+}
+
+// Reason declares a ReasonError for a reason within a domain, creating errors
+// having the Status Code.
+func Reason(domain, reason string, code codes.Code) *ReasonError {
+	return &ReasonError{domain: domain, reason: reason, code: code}
+}
+
+// Error implements error interface.
+func (r *ReasonError) Error() string {
+	return r.domain + ": " + r.reason
+}
+
+// New creates a new error having the Status Code and CausedError details of the
+// reason, enriched with any other details.
+//
+// When enabled with SetStackCapture, the stack of the caller is captured into
+// DebugError details.
+func (r *ReasonError) New(msg string, details ...Details) error {
+	return WithDetails(r.Wrap(newCoded(r.code, msg)), details...)
+}
+
+// Wrap wraps an error with the Status Code and CausedError details of the
+// reason, such that the ReasonError itself is a Details wrapper.
+func (r *ReasonError) Wrap(err error) error {
+	return r.WithMetadata(nil).Wrap(err)
+}
+
+// WithMetadata provides a Details wrapper to enrich errors with the Status
+// Code and CausedError details of the reason, having additional metadata.
+func (r *ReasonError) WithMetadata(metadata map[string]string) Details {
+	return wrapperFunc(func(err error) error {
+		return WithDetails(err, Code(r.code), Cause(&errdetails.ErrorInfo{
+			Reason:   r.reason,
+			Domain:   r.domain,
+			Metadata: metadata,
+		}))
+	})
+}
+
+// Code gets the Status Code of errors having the reason.
+func (r *ReasonError) Code() codes.Code {
+	return r.code
+}
+
+// GetDomain gets the logical grouping to which the reason belongs.
+func (r *ReasonError) GetDomain() string {
+	return r.domain
+}
+
+// GetReason gets the reason of the error.
+func (r *ReasonError) GetReason() string {
+	return r.reason
+}
+
+// GetMetadata gets no additional structured details, but satisfies the Info
+// interface such that a ReasonError can be given to Cause.
+func (r *ReasonError) GetMetadata() map[string]string {
+	return nil
+}
@@ -0,0 +1,57 @@
+package errdetails
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	errWalletEmpty  = Reason("wallet.platform.test", "WALLET_EMPTY", codes.ResourceExhausted)
+	errWalletFrozen = Reason("wallet.platform.test", "WALLET_FROZEN", codes.FailedPrecondition)
+)
+
+func TestReason(t *testing.T) {
+	testHandler(t)
+
+	err := errWalletEmpty.New("no more Redlines available")
+
+	require.True(t, errors.Is(err, errWalletEmpty), "expected error to be errWalletEmpty")
+	require.False(t, errors.Is(err, errWalletFrozen), "expected error not to be errWalletFrozen")
+	require.True(t, errors.Is(err, ErrResourceExhausted), "expected error to be ErrResourceExhausted")
+	require.Equal(t, "no more Redlines available", err.Error())
+
+	b, jerr := ToJSON(fmt.Errorf("failed to buy: %w", err))
+	require.NoError(t, jerr)
+
+	rebuilt := FromJSON(bytes.NewReader(b))
+	require.True(t, errors.Is(rebuilt, errWalletEmpty), "expected rebuilt error to be errWalletEmpty")
+	require.False(t, errors.Is(rebuilt, errWalletFrozen), "expected rebuilt error not to be errWalletFrozen")
+	require.True(t, errors.Is(rebuilt, ErrResourceExhausted), "expected rebuilt error to be ErrResourceExhausted")
+}
+
+func TestReasonDetails(t *testing.T) {
+	testHandler(t)
+
+	err := WithDetails(errors.New("wallet is frozen"),
+		errWalletFrozen.WithMetadata(map[string]string{"wallet": "123"}),
+	)
+	require.True(t, errors.Is(err, errWalletFrozen), "expected error to be errWalletFrozen")
+	require.True(t, errors.Is(err, ErrFailedPrecondition), "expected error to be ErrFailedPrecondition")
+
+	var causedErr CausedError
+	require.True(t, errors.As(err, &causedErr), "expected error to be CausedError")
+	require.Equal(t, "WALLET_FROZEN", causedErr.GetReason())
+	require.Equal(t, "123", causedErr.GetMetadata()["wallet"])
+
+	err = New(codes.Internal, "wallet is frozen", errWalletFrozen)
+	require.True(t, errors.Is(err, errWalletFrozen), "expected error to be errWalletFrozen")
+	require.True(t, errors.Is(err, ErrFailedPrecondition), "expected the reason's Status Code to apply")
+
+	err = WithCause(errors.New("wallet is frozen"), errWalletFrozen)
+	require.True(t, errors.Is(err, errWalletFrozen), "expected error to be errWalletFrozen")
+}
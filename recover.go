@@ -0,0 +1,84 @@
+package errdetails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// Recover wraps a HandlerFunc to recover from panics, returning the panic as
+// an Internal error having DebugError details of the panic value and the stack
+// where it panicked, for the HandlerFunc to serve the same as any other error.
+// The message of the error is "internal error", as the panic value may reveal
+// internals the client must not see.
+//
+// Recovered panics are reported to the ErrorHandler, including the panic value. A panic with
+// http.ErrAbortHandler is not recovered, so the response is aborted as
+// intended.
+func Recover(fn HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				err = recovered(v)
+			}
+		}()
+
+		return fn(w, r)
+	}
+}
+
+// assert RecoverUnaryServerInterceptor is of the same type UnaryServerInterceptor
+var _ grpc.UnaryServerInterceptor = RecoverUnaryServerInterceptor
+
+// RecoverUnaryServerInterceptor recovers from panics the same as Recover, and
+// transcribes wrapped errors with details into gRPC Status the same as
+// UnaryServerInterceptor.
+func RecoverUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = translateError(ctx, recovered(v))
+		}
+	}()
+
+	return UnaryServerInterceptor(ctx, req, info, handler)
+}
+
+// assert RecoverStreamServerInterceptor is of the same type StreamServerInterceptor
+var _ grpc.StreamServerInterceptor = RecoverStreamServerInterceptor
+
+// RecoverStreamServerInterceptor recovers from panics the same as Recover, and
+// transcribes wrapped errors with details into gRPC Status the same as
+// StreamServerInterceptor.
+func RecoverStreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = translateError(ss.Context(), recovered(v))
+		}
+	}()
+
+	return StreamServerInterceptor(srv, ss, info, handler)
+}
+
+// recovered turns a recovered panic value into an Internal error having the
+// panic value and the stack of the panic as debug details only, and reports it
+// to the ErrorHandler along with the panic value.
+//
+// It must be called directly by the deferred function recovering the panic.
+func recovered(v interface{}) error {
+	err := &errStack{
+		error:  &errCodeError{Code: codes.Internal, error: errors.New("internal error")},
+		detail: fmt.Sprint(v),
+		// skip callers, recovered, the deferred function, and runtime.gopanic
+		pcs: callers(5),
+	}
+
+	handler.Handle(fmt.Errorf("panic: %v: %w", v, err))
+	return err
+}
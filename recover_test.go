@@ -0,0 +1,107 @@
+package errdetails
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recoveredHandler collects errors reported to the ErrorHandler.
+type recoveredHandler []error
+
+func (h *recoveredHandler) Handle(err error) {
+	*h = append(*h, err)
+}
+
+func recoverHandler(t *testing.T) *recoveredHandler {
+	h := &recoveredHandler{}
+	SetErrorHandler(h)
+	t.Cleanup(func() { SetErrorHandler(nil) })
+	return h
+}
+
+func panicking() error {
+	panic("nil map")
+}
+
+func TestRecover(t *testing.T) {
+	reported := recoverHandler(t)
+
+	rr := httptest.NewRecorder()
+	Recover(func(w http.ResponseWriter, r *http.Request) error {
+		return panicking()
+	}).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+	require.Contains(t, rr.Body.String(), `"message":"internal error"`)
+	require.NotContains(t, rr.Body.String(), "panic:")
+	require.Contains(t, rr.Body.String(), "google.rpc.DebugInfo")
+
+	require.Len(t, *reported, 1)
+	err := (*reported)[0]
+	require.Equal(t, "panic: nil map: internal error", err.Error())
+	require.True(t, errors.Is(err, ErrInternal), "expected error to be ErrInternal")
+
+	var debugErr DebugError
+	require.True(t, errors.As(err, &debugErr), "expected error to be DebugError")
+	require.Equal(t, "nil map", debugErr.GetDetail())
+	require.True(t, strings.HasPrefix(debugErr.GetStackEntries()[0], "github.com/ClaudiaJ/errdetails.panicking "),
+		"expected stack to start where it panicked, got %q", debugErr.GetStackEntries()[0])
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	recoverHandler(t)
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		Recover(func(w http.ResponseWriter, r *http.Request) error {
+			panic(http.ErrAbortHandler)
+		}).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestRecoverUnaryServerInterceptor(t *testing.T) {
+	reported := recoverHandler(t)
+
+	_, err := RecoverUnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, panicking()
+	})
+
+	s, ok := status.FromError(err)
+	require.True(t, ok, "expected error to be a Status")
+	require.Equal(t, codes.Internal, s.Code())
+	require.Equal(t, "internal error", s.Message())
+	require.Len(t, *reported, 1)
+
+	_, err = RecoverUnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, New(codes.NotFound, "not found")
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRecoverStreamServerInterceptor(t *testing.T) {
+	reported := recoverHandler(t)
+
+	err := RecoverStreamServerInterceptor(nil, &testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		return panicking()
+	})
+
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Len(t, *reported, 1)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
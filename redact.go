@@ -0,0 +1,167 @@
+package errdetails
+
+import (
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Redactor drops or transforms anything in a Status not safe to share with the
+// client, before the response leaves the server.
+type Redactor interface {
+	Redact(*status.Status) *status.Status
+}
+
+// RedactorFunc type is an adapter to allow the use of ordinary functions as Redactor.
+type RedactorFunc func(*status.Status) *status.Status
+
+// Redact calls fn(s).
+func (fn RedactorFunc) Redact(s *status.Status) *status.Status {
+	return fn(s)
+}
+
+// Built-in Redactor presets.
+var (
+	// DevelopmentRedactor shares everything with the client.
+	DevelopmentRedactor Redactor = RedactorFunc(func(s *status.Status) *status.Status {
+		return s
+	})
+
+	// ProductionRedactor drops DebugInfo details, and masks the message of
+	// errors caused by the server itself.
+	ProductionRedactor Redactor = Redactors(
+		DropDetails(&errdetails.DebugInfo{}),
+		MaskMessage("internal error", codes.Unknown, codes.Internal, codes.DataLoss),
+	)
+)
+
+// Redactors chains each of the Redactors in order.
+func Redactors(redactors ...Redactor) Redactor {
+	return RedactorFunc(func(s *status.Status) *status.Status {
+		for _, r := range redactors {
+			s = r.Redact(s)
+		}
+		return s
+	})
+}
+
+// DropDetails drops details of the same message type as any of the messages,
+// e.g. DropDetails(&errdetails.DebugInfo{}).
+func DropDetails(msgs ...proto.Message) Redactor {
+	drop := make(map[protoreflect.FullName]bool, len(msgs))
+	for _, msg := range msgs {
+		drop[msg.ProtoReflect().Descriptor().FullName()] = true
+	}
+
+	return RedactorFunc(func(s *status.Status) *status.Status {
+		return mapDetails(s, func(detail *anypb.Any) *anypb.Any {
+			if drop[detail.MessageName()] {
+				return nil
+			}
+			return detail
+		})
+	})
+}
+
+// ScrubMetadata removes keys from the Metadata of ErrorInfo details.
+func ScrubMetadata(keys ...string) Redactor {
+	infoName := (&errdetails.ErrorInfo{}).ProtoReflect().Descriptor().FullName()
+
+	return RedactorFunc(func(s *status.Status) *status.Status {
+		return mapDetails(s, func(detail *anypb.Any) *anypb.Any {
+			if detail.MessageName() != infoName {
+				return detail
+			}
+
+			info := &errdetails.ErrorInfo{}
+			if err := detail.UnmarshalTo(info); err != nil {
+				// when in doubt, leave it out
+				return nil
+			}
+			for _, key := range keys {
+				delete(info.Metadata, key)
+			}
+
+			scrubbed, err := anypb.New(info)
+			if err != nil {
+				return nil
+			}
+			return scrubbed
+		})
+	})
+}
+
+// MaskMessage replaces the message of a Status having any of the codes, e.g.
+// to hide the messages of Internal errors from the client.
+func MaskMessage(msg string, masked ...codes.Code) Redactor {
+	return RedactorFunc(func(s *status.Status) *status.Status {
+		for _, code := range masked {
+			if s.Code() == code {
+				p := s.Proto()
+				p.Message = msg
+				return status.FromProto(p)
+			}
+		}
+		return s
+	})
+}
+
+// mapDetails replaces each of the details in a Status with the result of fn,
+// dropping details for which fn returns nil.
+func mapDetails(s *status.Status, fn func(*anypb.Any) *anypb.Any) *status.Status {
+	p := s.Proto()
+	if p == nil {
+		return s
+	}
+
+	details := p.Details[:0]
+	for _, detail := range p.Details {
+		if detail = fn(detail); detail != nil {
+			details = append(details, detail)
+		}
+	}
+	p.Details = details
+
+	return status.FromProto(p)
+}
+
+type statusRedactor struct {
+	mu       sync.RWMutex
+	redactor Redactor
+}
+
+// Redact implements Redactor interface.
+func (r *statusRedactor) Redact(s *status.Status) *status.Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if s == nil {
+		return s
+	}
+	return r.redactor.Redact(s)
+}
+
+var (
+	// redactor is applied to every Status before it's returned to the client.
+	redactor = &statusRedactor{redactor: DevelopmentRedactor}
+)
+
+// SetRedactor sets the package level Redactor applied to every Status before
+// it's returned to the client by HandlerFunc, ToJSON, and the server
+// interceptors.
+//
+// By default, or when set to nil, the DevelopmentRedactor shares everything
+// with the client.
+func SetRedactor(r Redactor) {
+	if r == nil {
+		r = DevelopmentRedactor
+	}
+
+	redactor.mu.Lock()
+	defer redactor.mu.Unlock()
+	redactor.redactor = r
+}
@@ -0,0 +1,69 @@
+package errdetails
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestProductionRedactor(t *testing.T) {
+	testHandler(t)
+
+	SetRedactor(Redactors(ProductionRedactor, ScrubMetadata("query")))
+	t.Cleanup(func() { SetRedactor(nil) })
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return New(codes.Internal, "pq: relation \"users\" does not exist",
+			Debug(&errdetails.DebugInfo{
+				StackEntries: []string{"data.Users/Get"},
+			}),
+			Cause(&errdetails.ErrorInfo{
+				Reason: "DATABASE_ERROR",
+				Domain: "users.platform.test",
+				Metadata: map[string]string{
+					"query": "SELECT * FROM users",
+					"table": "users",
+				},
+			}),
+		)
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.JSONEq(t, `{
+		"code": 13,
+		"message": "internal error",
+		"details": [{
+			"@type": "type.googleapis.com/google.rpc.ErrorInfo",
+			"reason": "DATABASE_ERROR",
+			"domain": "users.platform.test",
+			"metadata": {
+				"table": "users"
+			}
+		}]
+	}`, rr.Body.String())
+}
+
+func TestDevelopmentRedactor(t *testing.T) {
+	testHandler(t)
+
+	err := New(codes.Internal, "impossible error reached",
+		Debug(&errdetails.DebugInfo{Detail: "request body was nil"}),
+	)
+
+	b, jerr := ToJSON(err)
+	require.NoError(t, jerr)
+	require.JSONEq(t, `{
+		"code": 13,
+		"message": "impossible error reached",
+		"details": [{
+			"@type": "type.googleapis.com/google.rpc.DebugInfo",
+			"detail": "request body was nil"
+		}]
+	}`, string(b))
+}
@@ -0,0 +1,100 @@
+package errdetails
+
+import (
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// DetailFunc wraps an error with a protobuf message decoded from Status
+// details, reconstructing the error type the message was transcribed from.
+type DetailFunc func(err error, msg proto.Message) error
+
+// RegisterDetail registers a protobuf message type to be decoded from Status
+// details and wrapped into an error by the DetailFunc, enabling error types
+// built ontop protobuf messages not provided within this package to be
+// reconstructed by FromStatus and everything built on it the same as all of
+// the error types provided by this module.
+//
+// Registering a message type again replaces its DetailFunc.
+func RegisterDetail(mt protoreflect.MessageType, fn DetailFunc) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.types[mt.Descriptor().FullName()] = registeredDetail{MessageType: mt, wrap: fn}
+}
+
+type registeredDetail struct {
+	protoreflect.MessageType
+	wrap DetailFunc
+}
+
+type detailRegistry struct {
+	mu    sync.RWMutex
+	types map[protoreflect.FullName]registeredDetail
+}
+
+// unmarshal decodes a Status detail along with the DetailFunc registered for
+// its type. Unregistered types are resolved from the global protobuf registry
+// and wrapped as arbitrary errors.
+func (r *detailRegistry) unmarshal(detail *anypb.Any) (proto.Message, DetailFunc, error) {
+	r.mu.RLock()
+	d, ok := r.types[detail.MessageName()]
+	r.mu.RUnlock()
+
+	if !ok {
+		msg, err := detail.UnmarshalNew()
+		return msg, wrapArbitrary, err
+	}
+
+	msg := d.New().Interface()
+	if err := detail.UnmarshalTo(msg); err != nil {
+		return nil, nil, err
+	}
+	return msg, d.wrap, nil
+}
+
+func wrapArbitrary(err error, msg proto.Message) error {
+	return &arbitraryError{error: err, ProtoMessage: msg}
+}
+
+// registry holds the message types known to FromStatus, starting with all of
+// the errdetails messages provided by this package.
+var registry = &detailRegistry{
+	types: map[protoreflect.FullName]registeredDetail{},
+}
+
+func init() {
+	RegisterDetail((&errdetails.BadRequest{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errBadRequest{error: err, BadRequest: msg.(*errdetails.BadRequest)}
+	})
+	RegisterDetail((&errdetails.DebugInfo{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errDebugInfo{error: err, DebugInfo: msg.(*errdetails.DebugInfo)}
+	})
+	RegisterDetail((&errdetails.ErrorInfo{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errInfo{error: err, ErrorInfo: msg.(*errdetails.ErrorInfo)}
+	})
+	RegisterDetail((&errdetails.Help{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errHelpLink{error: err, Help: msg.(*errdetails.Help)}
+	})
+	RegisterDetail((&errdetails.LocalizedMessage{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &localizedError{error: err, LocalizedMessage: msg.(*errdetails.LocalizedMessage)}
+	})
+	RegisterDetail((&errdetails.PreconditionFailure{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errPreconditionFailed{error: err, PreconditionFailure: msg.(*errdetails.PreconditionFailure)}
+	})
+	RegisterDetail((&errdetails.QuotaFailure{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errQuotaFailure{error: err, QuotaFailure: msg.(*errdetails.QuotaFailure)}
+	})
+	RegisterDetail((&errdetails.RequestInfo{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errRequestInfo{error: err, RequestInfo: msg.(*errdetails.RequestInfo)}
+	})
+	RegisterDetail((&errdetails.ResourceInfo{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errResourceInfo{error: err, ResourceInfo: msg.(*errdetails.ResourceInfo)}
+	})
+	RegisterDetail((&errdetails.RetryInfo{}).ProtoReflect().Type(), func(err error, msg proto.Message) error {
+		return &errRetryInfo{error: err, RetryInfo: msg.(*errdetails.RetryInfo)}
+	})
+}
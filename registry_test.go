@@ -0,0 +1,47 @@
+package errdetails
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type attemptsError struct {
+	error
+	*wrapperspb.Int64Value
+}
+
+func (e *attemptsError) Unwrap() error {
+	return e.error
+}
+
+func TestRegisterDetail(t *testing.T) {
+	testHandler(t)
+
+	mt := (&wrapperspb.Int64Value{}).ProtoReflect().Type()
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		delete(registry.types, mt.Descriptor().FullName())
+	})
+
+	RegisterDetail(mt, func(err error, msg proto.Message) error {
+		return &attemptsError{error: err, Int64Value: msg.(*wrapperspb.Int64Value)}
+	})
+
+	err := New(codes.Aborted, "too many attempts", wrapperFunc(func(err error) error {
+		return &attemptsError{error: err, Int64Value: wrapperspb.Int64(3)}
+	}))
+
+	err = FromStatus(ToStatus(err))
+
+	require.True(t, errors.Is(err, ErrAborted), "expected error to be ErrAborted")
+
+	var attempts *attemptsError
+	require.True(t, errors.As(err, &attempts), "expected error to be rebuilt from registered detail")
+	require.EqualValues(t, 3, attempts.GetValue())
+}
@@ -0,0 +1,237 @@
+package errdetails
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy describes which failed requests are retried, and how long to
+// wait between attempts.
+//
+// The wait before each retry is an exponential backoff, or the retry delay of
+// a RetriableError if it's longer, and never extends past the deadline of the
+// context.
+type RetryPolicy struct {
+	// Codes are the Status Codes of errors to retry.
+	// If empty, Unavailable, ResourceExhausted, and Aborted errors are retried.
+	Codes []codes.Code
+
+	// MaxAttempts is the most attempts made, including the first.
+	// If zero, 3 attempts are made.
+	MaxAttempts int
+
+	// BaseDelay is the backoff before the first retry, doubled on each retry.
+	// If zero, the base delay is 100ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff, but not the retry delay of a RetriableError.
+	// If zero, the backoff is capped to 10s.
+	MaxDelay time.Duration
+
+	// Jitter randomizes the backoff by up to a fraction of itself, from 0 for
+	// no jitter up to 1.
+	Jitter float64
+}
+
+// DefaultRetryPolicy retries Unavailable, ResourceExhausted, and Aborted errors
+// up to 3 attempts with jittered exponential backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	Codes:       []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted},
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.2,
+}
+
+// RetriedError is an error returned by Retry, describing how many attempts
+// were made before giving up. An error of the first attempt is returned as is.
+type RetriedError interface {
+	error
+	Attempts() int
+}
+
+var _ RetriedError = (*errRetried)(nil)
+
+type errRetried struct {
+	error
+	attempts int
+}
+
+// Unwrap implements errors.Unwrap interface.
+func (e *errRetried) Unwrap() error {
+	return e.error
+}
+
+// Attempts gets how many attempts were made.
+func (e *errRetried) Attempts() int {
+	return e.attempts
+}
+
+// GRPCStatus implements interface required for status.FromError to turn the
+// error into a gRPC Status, the same Status as the last error.
+func (e *errRetried) GRPCStatus() *status.Status {
+	return ToStatus(e.error)
+}
+
+// StatusCode gets the HTTP status code of the last error.
+func (e *errRetried) StatusCode() int {
+	var sterr hasStatusCode
+	if errors.As(e.error, &sterr) {
+		return sterr.StatusCode()
+	}
+	return runtime.HTTPStatusFromCode(e.GRPCStatus().Code())
+}
+
+// retried wraps the last error of retried attempts.
+func retried(err error, attempts int) error {
+	if attempts <= 1 {
+		return err
+	}
+	return &errRetried{error: err, attempts: attempts}
+}
+
+// Retry calls fn until it succeeds, fails with an error not retried by the
+// policy, or runs out of attempts or time, returning the last error as a
+// RetriedError if there was more than one attempt.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !policy.retryable(err) {
+			return retried(err, attempt)
+		}
+
+		delay := policy.backoff(attempt)
+		var retErr RetriableError
+		if errors.As(err, &retErr) && retErr.GetRetryDelay() > delay {
+			delay = retErr.GetRetryDelay()
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return retried(err, attempt)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retried(err, attempt)
+		case <-timer.C:
+		}
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if len(p.Codes) == 0 {
+		p.Codes = DefaultRetryPolicy.Codes
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	return p
+}
+
+// retryable reports whether an error has any of the Status Codes of the policy.
+func (p RetryPolicy) retryable(err error) bool {
+	code := codes.Unknown
+	var sterr statusError
+	if errors.As(err, &sterr) {
+		code = sterr.GRPCStatus().Code()
+	}
+
+	for _, c := range p.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff gets the jittered exponential backoff before a retry.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// RetryUnaryClientInterceptor retries failed calls by the RetryPolicy, and
+// rebuilds wrapped errors with details from gRPC Status returned by the server
+// the same as UnaryClientInterceptor.
+func RetryUnaryClientInterceptor(policy RetryPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return Retry(ctx, policy, func(ctx context.Context) error {
+			return rebuildError(invoker(ctx, method, req, reply, cc, opts...))
+		})
+	}
+}
+
+// RetryTransport is an http.RoundTripper retrying failed requests by the
+// RetryPolicy, and rebuilding errors from responses having a 4xx or 5xx
+// status code the same as Transport.
+//
+// Requests having a body are only retried if the body can be replayed with
+// GetBody, as is the case for requests made with http.NewRequest.
+type RetryTransport struct {
+	// Base is the RoundTripper used to make requests.
+	// If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Policy describes which failed requests are retried.
+	Policy RetryPolicy
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := t.Policy
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		policy.MaxAttempts = 1
+	}
+
+	var res *http.Response
+	err := Retry(req.Context(), policy, func(ctx context.Context) error {
+		attempt := req.Clone(ctx)
+		if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			attempt.Body = body
+		}
+
+		var err error
+		res, err = (&Transport{Base: t.Base}).RoundTrip(attempt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
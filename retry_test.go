@@ -0,0 +1,147 @@
+package errdetails
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testRetryPolicy = RetryPolicy{
+	BaseDelay: time.Millisecond,
+	MaxDelay:  5 * time.Millisecond,
+}
+
+func TestRetry(t *testing.T) {
+	testHandler(t)
+
+	attempts := 0
+	err := Retry(context.Background(), testRetryPolicy, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return New(codes.Unavailable, "try again")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	testHandler(t)
+
+	for name, tt := range map[string]struct {
+		err      error
+		attempts int
+	}{
+		"exhausted":     {New(codes.Aborted, "conflict"), 3},
+		"not retryable": {New(codes.InvalidArgument, "bad request"), 1},
+		"plain error":   {errors.New("plain error"), 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Retry(context.Background(), testRetryPolicy, func(ctx context.Context) error {
+				return tt.err
+			})
+
+			require.True(t, errors.Is(err, tt.err), "expected the last error")
+			require.Equal(t, status.Code(tt.err), status.Code(err))
+
+			var retriedErr RetriedError
+			if tt.attempts == 1 {
+				require.Equal(t, tt.err, err, "expected the error of the first attempt as is")
+				return
+			}
+			require.True(t, errors.As(err, &retriedErr), "expected error to be RetriedError")
+			require.Equal(t, tt.attempts, retriedErr.Attempts())
+
+			// the Status must be found without unwrapping, as older gRPC releases do not unwrap
+			sterr, ok := err.(interface{ GRPCStatus() *status.Status })
+			require.True(t, ok, "expected error to have GRPCStatus")
+			require.Equal(t, status.Code(tt.err), sterr.GRPCStatus().Code())
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	testHandler(t)
+
+	start := time.Now()
+	attempts := 0
+	err := Retry(context.Background(), testRetryPolicy, func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return New(codes.ResourceExhausted, "slow down", RetryDelay(50*time.Millisecond))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+
+	// the retry delay would extend past the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	attempts = 0
+	err = Retry(ctx, testRetryPolicy, func(ctx context.Context) error {
+		attempts++
+		return New(codes.ResourceExhausted, "slow down", RetryDelay(time.Minute))
+	})
+	require.True(t, errors.Is(err, ErrResourceExhausted), "expected error to be ErrResourceExhausted")
+	require.Equal(t, 1, attempts)
+}
+
+func TestRetryTransport(t *testing.T) {
+	testHandler(t)
+
+	attempts := 0
+	srv := httptest.NewServer(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		require.Equal(t, "request body", string(body))
+
+		if attempts < 2 {
+			return New(codes.Unavailable, "try again")
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &RetryTransport{Policy: testRetryPolicy}}
+
+	res, err := client.Post(srv.URL, "text/plain", strings.NewReader("request body"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+	require.Equal(t, 2, attempts)
+
+	attempts = -10
+	_, err = client.Post(srv.URL, "text/plain", strings.NewReader("request body"))
+	require.True(t, errors.Is(err, ErrUnavailable), "expected error to be ErrUnavailable")
+
+	var retriedErr RetriedError
+	require.True(t, errors.As(err, &retriedErr), "expected error to be RetriedError")
+	require.Equal(t, 3, retriedErr.Attempts())
+}
+
+func TestRetryUnaryClientInterceptor(t *testing.T) {
+	testHandler(t)
+
+	attempts := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempts++
+		return status.Error(codes.Unavailable, "try again")
+	}
+
+	err := RetryUnaryClientInterceptor(testRetryPolicy)(context.Background(), "/test.Service/Method", nil, nil, nil, invoker)
+	require.True(t, errors.Is(err, ErrUnavailable), "expected error to be ErrUnavailable")
+	require.Equal(t, 3, attempts)
+}
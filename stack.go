@@ -0,0 +1,120 @@
+package errdetails
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxStackDepth is the most frames captured into a stack.
+const maxStackDepth = 32
+
+// captureStack enables capturing the stack of the caller of New when nonzero.
+var captureStack int32
+
+// SetStackCapture sets whether New captures the stack of its caller into
+// DebugError details, as if each error were created with the Stack wrapper.
+//
+// By default, stacks are only captured by the Stack wrapper.
+func SetStackCapture(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&captureStack, v)
+}
+
+func stackCaptureEnabled() bool {
+	return atomic.LoadInt32(&captureStack) != 0
+}
+
+// Stack provides a Details wrapper to enrich errors with DebugError details
+// having the stack of the function calling Stack.
+//
+// Only the program counters are captured, frames are symbolized into stack
+// entries once the error details are read or encoded.
+func Stack() Details {
+	pcs := callers(3)
+	return wrapperFunc(func(err error) error {
+		return &errStack{error: err, pcs: pcs}
+	})
+}
+
+// WithStack wraps an error with DebugError details having the stack of the
+// function calling WithStack.
+func WithStack(err error) DebugError {
+	return &errStack{error: err, pcs: callers(3)}
+}
+
+// callers captures program counters of the stack, skipping frames the same
+// as runtime.Callers relative to the function calling callers.
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	return pcs[:runtime.Callers(skip, pcs)]
+}
+
+var _ DebugError = (*errStack)(nil)
+
+// errStack is a DebugError symbolizing captured program counters into stack
+// entries only when it's needed.
+type errStack struct {
+	error
+	pcs    []uintptr
+	detail string
+
+	once sync.Once
+	info *errdetails.DebugInfo
+}
+
+// Unwrap implements errors.Unwrap interface.
+func (e *errStack) Unwrap() error {
+	return e.error
+}
+
+// ProtoReflect implements protoreflect.ProtoMessage interface, such that the
+// stack is transcribed into DebugInfo details.
+func (e *errStack) ProtoReflect() protoreflect.Message {
+	return e.debugInfo().ProtoReflect()
+}
+
+// GetDetail gets additonal debugging information provided by the server,
+// being the message of the error the stack was captured for unless given.
+func (e *errStack) GetDetail() string {
+	if e.detail == "" && e.error != nil {
+		return e.error.Error()
+	}
+	return e.detail
+}
+
+// GetStackEntries gets stack entries indicating where the error occurred.
+func (e *errStack) GetStackEntries() []string {
+	return e.debugInfo().GetStackEntries()
+}
+
+func (e *errStack) debugInfo() *errdetails.DebugInfo {
+	e.once.Do(func() {
+		entries := make([]string, 0, len(e.pcs))
+
+		frames := runtime.CallersFrames(e.pcs)
+		for {
+			frame, more := frames.Next()
+			if frame.Function != "" {
+				entries = append(entries, fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line))
+			}
+			if !more {
+				break
+			}
+		}
+
+		e.info = &errdetails.DebugInfo{
+			StackEntries: entries,
+			Detail:       e.GetDetail(),
+		}
+	})
+
+	return e.info
+}
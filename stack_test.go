@@ -0,0 +1,56 @@
+package errdetails
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStack(t *testing.T) {
+	testHandler(t)
+
+	err := New(codes.Internal, "impossible error reached", Stack())
+
+	var stack *errStack
+	require.True(t, errors.As(err, &stack), "expected error to be errStack")
+	require.Nil(t, stack.info, "expected stack not to be symbolized before it's needed")
+
+	details := ToStatus(err).Details()
+	require.Len(t, details, 1)
+
+	info, ok := details[0].(*errdetails.DebugInfo)
+	require.True(t, ok, "expected DebugInfo detail")
+	require.NotEmpty(t, info.GetStackEntries())
+	require.True(t, strings.HasPrefix(info.GetStackEntries()[0], "github.com/ClaudiaJ/errdetails.TestStack "),
+		"unexpected first stack entry %q", info.GetStackEntries()[0])
+	require.Equal(t, "impossible error reached", info.GetDetail())
+}
+
+func TestSetStackCapture(t *testing.T) {
+	testHandler(t)
+
+	SetStackCapture(true)
+	t.Cleanup(func() { SetStackCapture(false) })
+
+	err := New(codes.Internal, "impossible error reached")
+
+	var debugErr DebugError
+	require.True(t, errors.As(err, &debugErr), "expected error to be DebugError")
+	require.True(t, strings.HasPrefix(debugErr.GetStackEntries()[0], "github.com/ClaudiaJ/errdetails.TestSetStackCapture "),
+		"unexpected first stack entry %q", debugErr.GetStackEntries()[0])
+
+	require.Equal(t, "impossible error reached", debugErr.GetDetail())
+
+	err = Reason("wallet.platform.test", "WALLET_FROZEN", codes.FailedPrecondition).New("wallet is frozen")
+	require.True(t, errors.As(err, &debugErr), "expected error to be DebugError")
+	require.True(t, strings.HasPrefix(debugErr.GetStackEntries()[0], "github.com/ClaudiaJ/errdetails.TestSetStackCapture "),
+		"unexpected first stack entry %q", debugErr.GetStackEntries()[0])
+
+	// decoded the same as any other DebugInfo
+	require.True(t, proto.Equal(ToStatus(err).Proto(), ToStatus(FromStatus(ToStatus(err))).Proto()))
+}
@@ -0,0 +1,125 @@
+package errdetails
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ToStatus transcribes a wrapped error into a Status with all the details
+// intact, such that it can be recovered with FromStatus on the other side of
+// any transport able to carry a Status message.
+//
+// Details are ordered from the outermost layer of the wrapped error to the
+// innermost, and details of a message type repeated in layers of the wrapped
+// error are merged into a single Status detail, see SetMergeFunc.
+//
+// Errors joining multiple errors, like those of Join or errors.Join, are walked
+// depth-first with details collected from every joined error. The Status Code
+// and message are those of the first error found having a Status Code, the
+// same error errors.As would find.
+//
+// Errors not wrapped with a Status Code are classified with Classify, or else
+// become Unknown, and a nil error becomes a nil Status.
+func ToStatus(err error) *status.Status {
+	return toStatus(Classify(err))
+}
+
+// toStatus is ToStatus of an error already classified with Classify.
+func toStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	// become a Status one way or another
+	sterr := findStatus(err)
+	if sterr == nil {
+		sterr = &errCodeError{error: err, Code: codes.Unknown}
+	}
+
+	var msgs []proto.Message
+	Walk(err, func(msg proto.Message) bool {
+		msgs = append(msgs, msg)
+		return true
+	})
+
+	p := status.Convert(sterr).Proto()
+	if errors.Unwrap(sterr) != nil {
+		// details of an error wrapping other layers are collected from its layers
+		p.Details = nil
+	}
+	for _, msg := range merger.merge(msgs) {
+		// turn error details into protobuf details
+		any, aerr := anypb.New(msg)
+		if aerr != nil {
+			handler.Handle(fmt.Errorf("failed to marshal error detail: %w", aerr))
+			continue
+		}
+		p.Details = append(p.Details, any)
+	}
+
+	return status.FromProto(p)
+}
+
+// FromStatus makes best effort to reconstruct the wrapped error from a Status
+// such that errors.As and errors.Is may still be satisfied by the error
+// interface types. A nil or OK Status becomes a nil error.
+//
+// Details of a message type registered with RegisterDetail are wrapped by the
+// registered DetailFunc, all others are retained as arbitrary protobuf messages.
+//
+// The first of the details becomes the outermost layer of the resulting error,
+// the same order as ToStatus, such that a Status survives a round trip through
+// FromStatus and ToStatus unchanged.
+//
+// Should any of the details fail to unmarshal, the unmarshal error is returned
+// instead.
+func FromStatus(s *status.Status, mappers ...DetailsMapper) error {
+	sterr, err := fromStatus(s, mappers...)
+	if err != nil {
+		return err
+	}
+
+	return sterr
+}
+
+// fromStatus reconstructs the wrapped error from a Status, or fails with the
+// error of the first detail that can't be unmarshaled.
+func fromStatus(s *status.Status, mappers ...DetailsMapper) (rebuilt error, err error) {
+	if s.Code() == codes.OK {
+		return nil, nil
+	}
+
+	p := s.Proto()
+	var sterr error = &errCodeError{Code: codes.Code(p.Code), error: errors.New(p.Message)}
+
+	// wrap innermost first
+	for idx := len(p.Details) - 1; idx >= 0; idx-- {
+		detail := p.Details[idx]
+		msg, wrap, uerr := registry.unmarshal(detail)
+		if uerr != nil {
+			return nil, fmt.Errorf("failed to unmarshal status detail %q: %w", detail.GetTypeUrl(), uerr)
+		}
+
+		for _, mapper := range mappers {
+			if wrapper := mapper.Map(msg); wrapper != nil {
+				sterr = wrapper.Wrap(sterr)
+			}
+		}
+
+		sterr = wrap(sterr, msg)
+	}
+
+	return sterr, nil
+}
+
+// statusError is a neat little trick used in gRPC status module to enable
+// errors to self-describe a conversion to Status.
+type statusError interface {
+	error
+	GRPCStatus() *status.Status
+}
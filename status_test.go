@@ -0,0 +1,73 @@
+package errdetails
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestToStatus(t *testing.T) {
+	testHandler(t)
+
+	s := ToStatus(New(codes.NotFound, "no such user",
+		Resource(&errdetails.ResourceInfo{
+			ResourceType: "user",
+			ResourceName: "users/123",
+		}),
+	))
+
+	require.Equal(t, codes.NotFound, s.Code())
+	require.Equal(t, "no such user", s.Message())
+	require.Len(t, s.Details(), 1)
+	require.True(t, proto.Equal(&errdetails.ResourceInfo{
+		ResourceType: "user",
+		ResourceName: "users/123",
+	}, s.Details()[0].(proto.Message)))
+}
+
+func TestToStatusUnknown(t *testing.T) {
+	testHandler(t)
+
+	require.Nil(t, ToStatus(nil))
+	require.Equal(t, codes.Unknown, ToStatus(errors.New("plain error")).Code())
+}
+
+func TestFromStatus(t *testing.T) {
+	testHandler(t)
+
+	s, err := status.New(codes.PermissionDenied, "access denied").WithDetails(
+		&errdetails.ErrorInfo{
+			Reason: "ACCOUNT_LOCKED",
+			Domain: "auth.platform.test",
+		},
+		wrapperspb.String("arbitrary detail"),
+	)
+	require.NoError(t, err)
+
+	err = FromStatus(s)
+
+	require.True(t, errors.Is(err, ErrPermissionDenied), "expected error to be ErrPermissionDenied")
+
+	var causedErr CausedError
+	require.True(t, errors.As(err, &causedErr), "expected error to be CausedError")
+	require.Equal(t, "ACCOUNT_LOCKED", causedErr.GetReason())
+
+	var arbErr *arbitraryError
+	require.True(t, errors.As(err, &arbErr), "expected arbitrary detail to be retained")
+	require.Equal(t, "arbitrary detail", arbErr.ProtoMessage.(*wrapperspb.StringValue).GetValue())
+
+	require.Len(t, ToStatus(err).Details(), 2)
+}
+
+func TestFromStatusOK(t *testing.T) {
+	testHandler(t)
+
+	require.NoError(t, FromStatus(nil))
+	require.NoError(t, FromStatus(status.New(codes.OK, "")))
+}
@@ -0,0 +1,72 @@
+package errdetails
+
+import (
+	"errors"
+
+	"github.com/ClaudiaJ/errdetails/details"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// FieldError is a validation failure of a single field in a request, like
+// those reported by validation libraries.
+//
+// Should a FieldError also have a Reason() string method, the reason describes
+// the violation instead of the error message.
+type FieldError interface {
+	error
+	Field() string
+}
+
+type fieldReason interface {
+	Reason() string
+}
+
+type multiError interface {
+	Unwrap() []error
+}
+
+// FromValidation translates an error reported by request validation into an
+// InvalidArgument error having BadRequest details, with a field violation for
+// each FieldError found in the wrapped error tree.
+//
+// Errors joining multiple errors are walked through an Unwrap() []error method,
+// and wrapped errors through errors.Unwrap. A nil error stays nil.
+func FromValidation(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	wrappers := []Details{Code(codes.InvalidArgument)}
+	if violations := fieldViolations(err, nil); len(violations) > 0 {
+		wrappers = append(wrappers, BadRequest(violations...))
+	}
+
+	return WithDetails(err, wrappers...)
+}
+
+// fieldViolations appends a field violation for each FieldError in the error
+// tree, in depth-first order.
+func fieldViolations(err error, violations []details.FieldViolation) []details.FieldViolation {
+	switch e := err.(type) {
+	case nil:
+		return violations
+	case FieldError:
+		desc := e.Error()
+		if r, ok := e.(fieldReason); ok && r.Reason() != "" {
+			desc = r.Reason()
+		}
+
+		return append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       e.Field(),
+			Description: desc,
+		})
+	case multiError:
+		for _, err := range e.Unwrap() {
+			violations = fieldViolations(err, violations)
+		}
+		return violations
+	}
+
+	return fieldViolations(errors.Unwrap(err), violations)
+}
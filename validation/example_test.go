@@ -0,0 +1,28 @@
+package validation_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/ClaudiaJ/errdetails/validation"
+)
+
+func ExampleValidate() {
+	err := validation.Validate(
+		validation.Field("username", "", validation.Required),
+		validation.Field("password", "hunter", validation.Length(8, 0)),
+		validation.Field("roles", []string{"admin", "root"}, validation.Each(validation.OneOf("admin", "user"))),
+	)
+
+	var badErr errdetails.BadRequestError
+	if errors.As(err, &badErr) {
+		for _, v := range badErr.GetViolations() {
+			fmt.Printf("%s: %s\n", v.GetField(), v.GetDescription())
+		}
+	}
+	//output:
+	// username: must be set
+	// password: must have at least 8 characters
+	// roles[1]: must be one of [admin, user]
+}
@@ -0,0 +1,84 @@
+// Package playground adapts the ValidationErrors of go-playground/validator to
+// errdetails errors having BadRequest details.
+//
+// The adapter recognizes ValidationErrors by shape, such that this module does
+// not depend on go-playground/validator.
+package playground
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/ClaudiaJ/errdetails/details"
+	pb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// fieldError is the subset of validator.FieldError describing a violation.
+type fieldError interface {
+	error
+	Field() string
+	Namespace() string
+	Tag() string
+	Param() string
+}
+
+// FromValidation translates an error returned by validator.Struct into an
+// InvalidArgument error having BadRequest details, with a field violation for
+// each of the wrapped ValidationErrors.
+//
+// Field paths are namespaced relative to the validated struct, e.g.
+// "Addresses[0].City". Errors other than ValidationErrors are returned as is.
+func FromValidation(err error) error {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if violations, ok := fieldViolations(e); ok {
+			return errdetails.WithDetails(err,
+				errdetails.Code(codes.InvalidArgument),
+				errdetails.BadRequest(violations...),
+			)
+		}
+	}
+
+	return err
+}
+
+// fieldViolations translates an error if it's a slice of FieldErrors, like
+// validator.ValidationErrors.
+func fieldViolations(err error) ([]details.FieldViolation, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return nil, false
+	}
+
+	violations := make([]details.FieldViolation, 0, v.Len())
+	for idx := 0; idx < v.Len(); idx++ {
+		ferr, ok := v.Index(idx).Interface().(fieldError)
+		if !ok {
+			return nil, false
+		}
+		violations = append(violations, fieldViolation(ferr))
+	}
+
+	return violations, true
+}
+
+func fieldViolation(ferr fieldError) *pb.BadRequest_FieldViolation {
+	field := ferr.Field()
+	if ns := ferr.Namespace(); strings.Contains(ns, ".") {
+		// drop the name of the validated struct
+		field = ns[strings.Index(ns, ".")+1:]
+	}
+
+	tag := ferr.Tag()
+	if ferr.Param() != "" {
+		tag += "=" + ferr.Param()
+	}
+
+	return &pb.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf("failed on the '%s' tag", tag),
+	}
+}
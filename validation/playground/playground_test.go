@@ -0,0 +1,56 @@
+package playground
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/stretchr/testify/require"
+)
+
+// testFieldError has the same shape as validator.FieldError.
+type testFieldError struct {
+	ns, field, tag, param string
+}
+
+func (e testFieldError) Error() string {
+	return fmt.Sprintf("Key: '%s' Error:Field validation for '%s' failed on the '%s' tag", e.ns, e.field, e.tag)
+}
+func (e testFieldError) Field() string     { return e.field }
+func (e testFieldError) Namespace() string { return e.ns }
+func (e testFieldError) Tag() string       { return e.tag }
+func (e testFieldError) Param() string     { return e.param }
+
+// testValidationErrors has the same shape as validator.ValidationErrors.
+type testValidationErrors []testFieldError
+
+func (e testValidationErrors) Error() string {
+	return "validation failed"
+}
+
+func TestFromValidation(t *testing.T) {
+	err := FromValidation(fmt.Errorf("invalid user: %w", testValidationErrors{
+		{ns: "User.Name", field: "Name", tag: "required"},
+		{ns: "User.Addresses[0].City", field: "City", tag: "min", param: "3"},
+	}))
+
+	require.True(t, errors.Is(err, errdetails.ErrInvalidArgument), "expected error to be ErrInvalidArgument")
+	require.Equal(t, "invalid user: validation failed", err.Error())
+
+	var badErr errdetails.BadRequestError
+	require.True(t, errors.As(err, &badErr), "expected error to be BadRequestError")
+
+	violations := badErr.GetViolations()
+	require.Len(t, violations, 2)
+	require.Equal(t, "Name", violations[0].GetField())
+	require.Equal(t, "failed on the 'required' tag", violations[0].GetDescription())
+	require.Equal(t, "Addresses[0].City", violations[1].GetField())
+	require.Equal(t, "failed on the 'min=3' tag", violations[1].GetDescription())
+}
+
+func TestFromValidationOther(t *testing.T) {
+	err := errors.New("invalid validation")
+	require.Equal(t, err, FromValidation(err))
+	require.Nil(t, FromValidation(nil))
+}
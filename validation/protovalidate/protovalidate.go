@@ -0,0 +1,143 @@
+// Package protovalidate adapts the ValidationError of protovalidate to
+// errdetails errors having BadRequest details.
+//
+// The adapter recognizes a ValidationError by its ToProto method returning
+// buf.validate.Violations, such that this module does not depend on
+// protovalidate.
+package protovalidate
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/ClaudiaJ/errdetails/details"
+	pb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const violationsName protoreflect.FullName = "buf.validate.Violations"
+
+// FromValidation translates an error returned by protovalidate.Validate into an
+// InvalidArgument error having BadRequest details, with a field violation for
+// each violation of the wrapped ValidationError.
+//
+// Field paths are rendered like "addresses[0].city" or `labels["env"]`.
+// Errors other than a ValidationError are returned as is.
+func FromValidation(err error) error {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if violations, ok := fieldViolations(e); ok {
+			return errdetails.WithDetails(err,
+				errdetails.Code(codes.InvalidArgument),
+				errdetails.BadRequest(violations...),
+			)
+		}
+	}
+
+	return err
+}
+
+// fieldViolations translates an error if it has a ToProto method returning
+// buf.validate.Violations, like protovalidate.ValidationError.
+func fieldViolations(err error) ([]details.FieldViolation, bool) {
+	method := reflect.ValueOf(err).MethodByName("ToProto")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil, false
+	}
+
+	msg, ok := method.Call(nil)[0].Interface().(proto.Message)
+	if !ok || msg.ProtoReflect().Descriptor().FullName() != violationsName {
+		return nil, false
+	}
+
+	m := msg.ProtoReflect()
+	list := m.Get(m.Descriptor().Fields().ByName("violations")).List()
+
+	violations := make([]details.FieldViolation, 0, list.Len())
+	for idx := 0; idx < list.Len(); idx++ {
+		violations = append(violations, fieldViolation(list.Get(idx).Message()))
+	}
+
+	return violations, true
+}
+
+// fieldViolation translates a buf.validate.Violation, having either a
+// field_path string or a structured field path.
+func fieldViolation(v protoreflect.Message) *pb.BadRequest_FieldViolation {
+	desc := getString(v, "message")
+	if desc == "" {
+		desc = getString(v, "rule_id") + getString(v, "constraint_id")
+	}
+
+	field := getString(v, "field_path")
+	if fd := v.Descriptor().Fields().ByName("field"); field == "" && fd != nil && fd.Message() != nil {
+		field = fieldPath(v.Get(fd).Message())
+	}
+
+	return &pb.BadRequest_FieldViolation{
+		Field:       field,
+		Description: desc,
+	}
+}
+
+// fieldPath renders a buf.validate.FieldPath like `a.b[0].c["k"]`.
+func fieldPath(path protoreflect.Message) string {
+	fd := path.Descriptor().Fields().ByName("elements")
+	if fd == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	elements := path.Get(fd).List()
+	for idx := 0; idx < elements.Len(); idx++ {
+		elem := elements.Get(idx).Message()
+		if idx > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(getString(elem, "field_name"))
+
+		fields := elem.Descriptor().Fields()
+		for _, name := range []protoreflect.Name{"index", "bool_key", "int_key", "uint_key", "string_key"} {
+			fd := fields.ByName(name)
+			if fd == nil || !elem.Has(fd) {
+				continue
+			}
+
+			b.WriteString("[" + subscript(elem.Get(fd).Interface()) + "]")
+		}
+	}
+
+	return b.String()
+}
+
+// subscript renders a list index or map key of a field path element.
+func subscript(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	}
+	return ""
+}
+
+// getString gets a string field by name, if the message has it.
+func getString(m protoreflect.Message, name protoreflect.Name) string {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.Kind() != protoreflect.StringKind {
+		return ""
+	}
+	return m.Get(fd).String()
+}
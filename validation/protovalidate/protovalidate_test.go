@@ -0,0 +1,156 @@
+package protovalidate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// validateFile describes the parts of buf/validate/validate.proto read by the
+// adapter, having both the legacy field_path and the structured field path.
+var validateFile = func() protoreflect.FileDescriptor {
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptorpb.FieldDescriptorProto {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			fd.TypeName = proto.String(typeName)
+		}
+		return fd
+	}
+	oneof := func(fd *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		fd.OneofIndex = proto.Int32(0)
+		return fd
+	}
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("buf/validate/validate.proto"),
+		Package: proto.String("buf.validate"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Violations"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("violations", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".buf.validate.Violation", true),
+			},
+		}, {
+			Name: proto.String("Violation"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("field_path", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+				field("constraint_id", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+				field("message", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+				field("field", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".buf.validate.FieldPath", false),
+			},
+		}, {
+			Name: proto.String("FieldPath"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("elements", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".buf.validate.FieldPathElement", true),
+			},
+		}, {
+			Name: proto.String("FieldPathElement"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("field_name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false),
+				oneof(field("index", 6, descriptorpb.FieldDescriptorProto_TYPE_UINT64, "", false)),
+				oneof(field("string_key", 10, descriptorpb.FieldDescriptorProto_TYPE_STRING, "", false)),
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("subscript")}},
+		}},
+	}, nil)
+	if err != nil {
+		panic(err)
+	}
+	return fd
+}()
+
+// testValidationError has the same shape as protovalidate.ValidationError.
+type testValidationError struct {
+	violations proto.Message
+}
+
+func (e *testValidationError) Error() string {
+	return "validation error"
+}
+
+func (e *testValidationError) ToProto() proto.Message {
+	return e.violations
+}
+
+func newMessage(name protoreflect.Name, fields map[protoreflect.Name]interface{}) protoreflect.Message {
+	m := dynamicpb.NewMessage(validateFile.Messages().ByName(name))
+	for name, v := range fields {
+		fd := m.Descriptor().Fields().ByName(name)
+		switch v := v.(type) {
+		case []protoreflect.Message:
+			list := m.Mutable(fd).List()
+			for _, elem := range v {
+				list.Append(protoreflect.ValueOfMessage(elem))
+			}
+		case protoreflect.Message:
+			m.Set(fd, protoreflect.ValueOfMessage(v))
+		default:
+			m.Set(fd, protoreflect.ValueOf(v))
+		}
+	}
+	return m
+}
+
+func TestFromValidation(t *testing.T) {
+	violations := newMessage("Violations", map[protoreflect.Name]interface{}{
+		"violations": []protoreflect.Message{
+			newMessage("Violation", map[protoreflect.Name]interface{}{
+				"field_path":    "name",
+				"constraint_id": "required",
+			}),
+			newMessage("Violation", map[protoreflect.Name]interface{}{
+				"message": "value length must be at least 3 characters",
+				"field": newMessage("FieldPath", map[protoreflect.Name]interface{}{
+					"elements": []protoreflect.Message{
+						newMessage("FieldPathElement", map[protoreflect.Name]interface{}{
+							"field_name": "addresses",
+							"index":      uint64(1),
+						}),
+						newMessage("FieldPathElement", map[protoreflect.Name]interface{}{
+							"field_name": "labels",
+							"string_key": "env",
+						}),
+					},
+				}),
+			}),
+		},
+	})
+
+	err := FromValidation(fmt.Errorf("invalid user: %w", &testValidationError{violations: violations.Interface()}))
+
+	require.True(t, errors.Is(err, errdetails.ErrInvalidArgument), "expected error to be ErrInvalidArgument")
+
+	var badErr errdetails.BadRequestError
+	require.True(t, errors.As(err, &badErr), "expected error to be BadRequestError")
+
+	got := badErr.GetViolations()
+	require.Len(t, got, 2)
+	require.Equal(t, "name", got[0].GetField())
+	require.Equal(t, "required", got[0].GetDescription())
+	require.Equal(t, `addresses[1].labels["env"]`, got[1].GetField())
+	require.Equal(t, "value length must be at least 3 characters", got[1].GetDescription())
+}
+
+func TestFromValidationOther(t *testing.T) {
+	err := errors.New("compilation error")
+	require.Equal(t, err, FromValidation(err))
+	require.Nil(t, FromValidation(nil))
+}
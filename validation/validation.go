@@ -0,0 +1,342 @@
+// Package validation validates requests with rules composed in Go, reporting
+// every violation at once as an InvalidArgument error having BadRequest
+// details.
+//
+//	err := validation.Validate(
+//		validation.Field("username", req.Username, validation.Required, validation.Length(3, 32)),
+//		validation.Field("emails", req.Emails, validation.Each(validation.Pattern(`^\S+@\S+$`))),
+//		validation.Field("address", req.Address, validation.Struct),
+//	)
+//
+// Adapters from other validation libraries are provided by its subpackages.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/ClaudiaJ/errdetails/details"
+	pb "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// Rule validates the value of a field, describing each way the value violates
+// the rule.
+type Rule interface {
+	Validate(path string, value interface{}) []details.FieldViolation
+}
+
+// RuleFunc type is an adapter to allow the use of ordinary functions as Rule.
+type RuleFunc func(path string, value interface{}) []details.FieldViolation
+
+// Validate calls fn(path, value).
+func (fn RuleFunc) Validate(path string, value interface{}) []details.FieldViolation {
+	return fn(path, value)
+}
+
+// Validatable is a value validating itself, like a nested request message.
+type Validatable interface {
+	Validate() error
+}
+
+// Built-in Rules.
+//
+// Every Rule other than Required and Struct is satisfied by an empty value, i.e.
+// nil, the zero value, or a string, slice, or map of length zero.
+var (
+	// Required violates empty values.
+	Required Rule = RuleFunc(func(path string, value interface{}) []details.FieldViolation {
+		if isEmpty(value) {
+			return violation(path, "must be set")
+		}
+		return nil
+	})
+
+	// Struct validates a Validatable value, or a value whose pointer is
+	// Validatable, reporting violations of the nested fields relative to the path
+	// of the value. Only nil values are skipped, such that Required fields of a
+	// zero value struct are violated.
+	Struct Rule = RuleFunc(func(path string, value interface{}) []details.FieldViolation {
+		if isNil(value) {
+			return nil
+		}
+
+		v, ok := validatable(value)
+		if !ok {
+			return violation(path, "must be validatable")
+		}
+
+		err := v.Validate()
+		if err == nil {
+			return nil
+		}
+
+		var badErr errdetails.BadRequestError
+		if !errors.As(err, &badErr) {
+			return violation(path, err.Error())
+		}
+
+		var violations []details.FieldViolation
+		for _, v := range badErr.GetViolations() {
+			violations = append(violations, &pb.BadRequest_FieldViolation{
+				Field:       string(errdetails.FieldPath(path).Join(errdetails.FieldPath(v.GetField()))),
+				Description: v.GetDescription(),
+			})
+		}
+		return violations
+	})
+)
+
+// Length violates strings having fewer or more characters than the bounds, and
+// slices or maps having fewer or more items. A max of zero is unbounded.
+func Length(min, max int) Rule {
+	return RuleFunc(func(path string, value interface{}) []details.FieldViolation {
+		if isEmpty(value) {
+			return nil
+		}
+
+		v := indirect(value)
+		unit, n := "items", 0
+		switch v.Kind() {
+		case reflect.String:
+			unit, n = "characters", utf8.RuneCountInString(v.String())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			n = v.Len()
+		default:
+			return violation(path, "must have a length")
+		}
+
+		switch {
+		case n >= min && (max <= 0 || n <= max):
+			return nil
+		case max <= 0:
+			return violation(path, fmt.Sprintf("must have at least %d %s", min, unit))
+		case min <= 0:
+			return violation(path, fmt.Sprintf("must have at most %d %s", max, unit))
+		}
+		return violation(path, fmt.Sprintf("must have between %d and %d %s", min, max, unit))
+	})
+}
+
+// Pattern violates strings not matching a regular expression. It panics if the
+// expression can't be parsed, the same as regexp.MustCompile.
+func Pattern(expr string) Rule {
+	re := regexp.MustCompile(expr)
+
+	return RuleFunc(func(path string, value interface{}) []details.FieldViolation {
+		if isEmpty(value) {
+			return nil
+		}
+
+		v := indirect(value)
+		if v.Kind() != reflect.String || !re.MatchString(v.String()) {
+			return violation(path, fmt.Sprintf("must match pattern %q", expr))
+		}
+		return nil
+	})
+}
+
+// OneOf violates values not equal to any of the allowed values. Numbers are
+// compared by value whatever their type, such that an int32 enum value matches
+// untyped constants, and other values are compared as the type of the allowed
+// value when of the same kind, such that a value of a named type like
+// `type Currency string` matches its constants.
+func OneOf(allowed ...interface{}) Rule {
+	names := make([]string, len(allowed))
+	for idx, a := range allowed {
+		names[idx] = fmt.Sprint(a)
+	}
+	desc := fmt.Sprintf("must be one of [%s]", strings.Join(names, ", "))
+
+	return RuleFunc(func(path string, value interface{}) []details.FieldViolation {
+		if isEmpty(value) {
+			return nil
+		}
+
+		v := indirect(value)
+		for _, a := range allowed {
+			if equal(v, a) {
+				return nil
+			}
+		}
+		return violation(path, desc)
+	})
+}
+
+// Each validates each item of a slice, or each value of a map, with the rules,
+// e.g. at paths "tags[0]" or `labels["env"]`.
+func Each(rules ...Rule) Rule {
+	return RuleFunc(func(path string, value interface{}) []details.FieldViolation {
+		if isEmpty(value) {
+			return nil
+		}
+
+		var violations []details.FieldViolation
+		validate := func(path string, item reflect.Value) {
+			for _, rule := range rules {
+				violations = append(violations, rule.Validate(path, item.Interface())...)
+			}
+		}
+
+		v := indirect(value)
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			for idx := 0; idx < v.Len(); idx++ {
+				validate(string(errdetails.FieldPath(path).Index(idx)), v.Index(idx))
+			}
+		case reflect.Map:
+			type entry struct {
+				subscript string
+				value     reflect.Value
+			}
+
+			iter := v.MapRange()
+			entries := make([]entry, 0, v.Len())
+			for iter.Next() {
+				entries = append(entries, entry{subscript: mapKey(iter.Key()), value: iter.Value()})
+			}
+
+			// validate in a stable order
+			sort.Slice(entries, func(i, j int) bool {
+				return entries[i].subscript < entries[j].subscript
+			})
+			for _, e := range entries {
+				validate(path+"["+e.subscript+"]", e.value)
+			}
+		default:
+			return violation(path, "must be a list or map")
+		}
+
+		return violations
+	})
+}
+
+// FieldRules are the rules validating the value of a named field.
+type FieldRules struct {
+	name  string
+	value interface{}
+	rules []Rule
+}
+
+// Field validates the value of a named field with the rules, in order.
+func Field(name string, value interface{}, rules ...Rule) FieldRules {
+	return FieldRules{name: name, value: value, rules: rules}
+}
+
+// Validate validates each of the fields, returning an InvalidArgument
+// BadRequestError describing every violation, or nil if there are none.
+func Validate(fields ...FieldRules) error {
+	var violations []details.FieldViolation
+	for _, field := range fields {
+		for _, rule := range field.rules {
+			violations = append(violations, rule.Validate(field.name, field.value)...)
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return errdetails.WithBadRequest(errdetails.New(codes.InvalidArgument, "invalid request"), violations...)
+}
+
+func violation(path, desc string) []details.FieldViolation {
+	return []details.FieldViolation{&pb.BadRequest_FieldViolation{
+		Field:       path,
+		Description: desc,
+	}}
+}
+
+// mapKey renders a map key as a path subscript, quoting strings the same as
+// FieldPath.Key.
+func mapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return strconv.Quote(key.String())
+	}
+	return fmt.Sprint(key.Interface())
+}
+
+// indirect dereferences pointers to the underlying value.
+func indirect(value interface{}) reflect.Value {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// validatable returns the value as Validatable, or else a pointer to a copy of
+// the value, for values whose Validate method has a pointer receiver.
+func validatable(value interface{}) (Validatable, bool) {
+	if v, ok := value.(Validatable); ok {
+		return v, true
+	}
+
+	v := reflect.ValueOf(value)
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	p, ok := ptr.Interface().(Validatable)
+	return p, ok
+}
+
+// equal reports whether a value equals an allowed value, comparing numbers by
+// value, and converting other values to the type of the allowed value when
+// they are of the same kind.
+func equal(v reflect.Value, allowed interface{}) bool {
+	a := reflect.ValueOf(allowed)
+	if x, ok := number(v); ok {
+		y, ok := number(a)
+		return ok && x.Cmp(y) == 0
+	}
+
+	if a.IsValid() && v.Kind() == a.Kind() && v.Type().ConvertibleTo(a.Type()) {
+		v = v.Convert(a.Type())
+	}
+	return reflect.DeepEqual(v.Interface(), allowed)
+}
+
+// number returns the value of an integer or floating-point number exactly.
+func number(v reflect.Value) (*big.Float, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(v.Float()) {
+			return nil, false
+		}
+		return new(big.Float).SetFloat64(v.Float()), true
+	}
+	return nil, false
+}
+
+// isNil reports whether a value is nil, or a nil pointer.
+func isNil(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	return !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil())
+}
+
+// isEmpty reports whether a value is nil, the zero value, or a string, slice,
+// or map of length zero. A pointer to any value is not empty.
+func isEmpty(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ClaudiaJ/errdetails"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City    string
+	Country string
+}
+
+func (a *address) Validate() error {
+	return Validate(
+		Field("city", a.City, Required),
+		Field("country", a.Country, Required, OneOf("CA", "US")),
+	)
+}
+
+type testViolation struct {
+	field, desc string
+}
+
+func testViolations(t *testing.T, err error) []testViolation {
+	t.Helper()
+
+	require.True(t, errors.Is(err, errdetails.ErrInvalidArgument), "expected error to be ErrInvalidArgument")
+
+	var badErr errdetails.BadRequestError
+	require.True(t, errors.As(err, &badErr), "expected error to be BadRequestError")
+
+	var got []testViolation
+	for _, v := range badErr.GetViolations() {
+		got = append(got, testViolation{v.GetField(), v.GetDescription()})
+	}
+	return got
+}
+
+func TestValidate(t *testing.T) {
+	err := Validate(
+		Field("username", "", Required, Length(3, 32)),
+		Field("nickname", "ab", Length(3, 0)),
+		Field("bio", "too long", Length(0, 3)),
+		Field("email", "not an email", Pattern(`^\S+@\S+$`)),
+		Field("tags", []string{"ok", "a"}, Length(1, 5), Each(Length(2, 10))),
+		Field("labels", map[string]string{"env": "", "team": "errors"}, Each(Required)),
+		Field("addresses", []*address{{City: "Montréal", Country: "CA"}, {Country: "FR"}}, Each(Struct)),
+		Field("shipping", &address{City: "Boise", Country: "US"}, Struct),
+		Field("billing", (*address)(nil), Struct),
+	)
+
+	require.Equal(t, []testViolation{
+		{"username", "must be set"},
+		{"nickname", "must have at least 3 characters"},
+		{"bio", "must have at most 3 characters"},
+		{"email", `must match pattern "^\\S+@\\S+$"`},
+		{"tags[1]", "must have between 2 and 10 characters"},
+		{`labels["env"]`, "must be set"},
+		{"addresses[1].city", "must be set"},
+		{"addresses[1].country", "must be one of [CA, US]"},
+	}, testViolations(t, err))
+}
+
+func TestValidateEmpty(t *testing.T) {
+	require.NoError(t, Validate(
+		Field("nickname", "", Length(3, 0), Pattern(`^\w+$`), OneOf("a", "b")),
+		Field("tags", []string(nil), Each(Required)),
+		Field("shipping", (*address)(nil), Struct),
+	))
+}
+
+type currency string
+
+const (
+	currencyCAD currency = "CAD"
+	currencyUSD currency = "USD"
+)
+
+type money struct {
+	Amount   int
+	Currency currency
+}
+
+func (m money) Validate() error {
+	return Validate(
+		Field("amount", m.Amount, Required),
+		Field("currency", m.Currency, Required, OneOf(currencyCAD, currencyUSD)),
+	)
+}
+
+func TestValidateZeroStruct(t *testing.T) {
+	err := Validate(
+		Field("price", money{}, Struct),
+		Field("shipping", &address{}, Struct),
+		Field("billing", address{}, Struct),
+	)
+
+	require.Equal(t, []testViolation{
+		{"price.amount", "must be set"},
+		{"price.currency", "must be set"},
+		{"shipping.city", "must be set"},
+		{"shipping.country", "must be set"},
+		{"billing.city", "must be set"},
+		{"billing.country", "must be set"},
+	}, testViolations(t, err))
+}
+
+func TestStructNotValidatable(t *testing.T) {
+	err := Validate(Field("thing", struct{ Name string }{}, Struct))
+
+	require.Equal(t, []testViolation{
+		{"thing", "must be validatable"},
+	}, testViolations(t, err))
+}
+
+func TestOneOfNamedType(t *testing.T) {
+	require.NoError(t, Validate(
+		Field("price", money{Amount: 100, Currency: currencyCAD}, Struct),
+		Field("currency", currencyUSD, OneOf("CAD", "USD")),
+		Field("code", "CAD", OneOf(currencyCAD, currencyUSD)),
+	))
+
+	err := Validate(Field("price", money{Amount: 100, Currency: "EUR"}, Struct))
+	require.Equal(t, []testViolation{
+		{"price.currency", "must be one of [CAD, USD]"},
+	}, testViolations(t, err))
+}
+
+type state int32
+
+const (
+	stateActive state = 1
+	stateFrozen state = 2
+)
+
+func TestOneOfNumbers(t *testing.T) {
+	require.NoError(t, Validate(
+		Field("state", int32(2), OneOf(1, 2)),
+		Field("enum", stateFrozen, OneOf(1, 2)),
+		Field("count", 1, OneOf(stateActive, stateFrozen)),
+		Field("size", uint8(2), OneOf(1, 2)),
+		Field("ratio", 0.5, OneOf(0.5, 1)),
+	))
+
+	err := Validate(
+		Field("state", int32(3), OneOf(1, 2)),
+		Field("ratio", 1.5, OneOf(1, 2)),
+		Field("name", "1", OneOf(1, 2)),
+	)
+	require.Equal(t, []testViolation{
+		{"state", "must be one of [1, 2]"},
+		{"ratio", "must be one of [1, 2]"},
+		{"name", "must be one of [1, 2]"},
+	}, testViolations(t, err))
+}
+
+type plainValidatable struct{}
+
+func (plainValidatable) Validate() error {
+	return errors.New("impossible")
+}
+
+func TestStructError(t *testing.T) {
+	err := Validate(Field("thing", &plainValidatable{}, Struct))
+
+	require.Equal(t, []testViolation{
+		{"thing", "impossible"},
+	}, testViolations(t, err))
+}
@@ -0,0 +1,63 @@
+package errdetails
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testFieldError struct {
+	field, reason string
+}
+
+func (e *testFieldError) Error() string {
+	return e.field + " is invalid"
+}
+
+func (e *testFieldError) Field() string {
+	return e.field
+}
+
+func (e *testFieldError) Reason() string {
+	return e.reason
+}
+
+type testMultiError []error
+
+func (e testMultiError) Error() string {
+	return "multiple errors"
+}
+
+func (e testMultiError) Unwrap() []error {
+	return e
+}
+
+func TestFromValidation(t *testing.T) {
+	testHandler(t)
+
+	err := FromValidation(fmt.Errorf("invalid request: %w", testMultiError{
+		&testFieldError{field: "name", reason: "must be set"},
+		errors.New("not a field error"),
+		testMultiError{
+			fmt.Errorf("nested: %w", &testFieldError{field: "email"}),
+		},
+	}))
+
+	require.True(t, errors.Is(err, ErrInvalidArgument), "expected error to be ErrInvalidArgument")
+	require.Equal(t, "invalid request: multiple errors", err.Error())
+
+	var badErr BadRequestError
+	require.True(t, errors.As(err, &badErr), "expected error to be BadRequestError")
+
+	violations := badErr.GetViolations()
+	require.Len(t, violations, 2)
+	require.Equal(t, "name", violations[0].GetField())
+	require.Equal(t, "must be set", violations[0].GetDescription())
+	require.Equal(t, "email", violations[1].GetField())
+	require.Equal(t, "email is invalid", violations[1].GetDescription())
+
+	require.Nil(t, FromValidation(nil))
+	require.True(t, errors.Is(FromValidation(errors.New("invalid")), ErrInvalidArgument))
+}
@@ -0,0 +1,41 @@
+package errdetails
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Walk calls fn with the details of each layer of a wrapped error, from the
+// outermost layer to the innermost, until fn returns false.
+//
+// Errors joining multiple errors are walked depth-first the same as ToStatus,
+// and details are the protobuf messages the layers are built on, e.g. the
+// errdetails.BadRequest of a BadRequestError, or the message retained by
+// FromStatus for a message type that isn't registered.
+func Walk(err error, fn func(proto.Message) bool) {
+	walk(err, func(err error) bool {
+		if msg, ok := err.(protoreflect.ProtoMessage); ok {
+			return fn(msg.ProtoReflect().Interface())
+		}
+		return true
+	})
+}
+
+// DetailsOf collects every detail of a message type in a wrapped error, from
+// the outermost layer to the innermost, e.g.
+//
+//	for _, info := range errdetails.DetailsOf[*errdetails.ErrorInfo](err) {
+//		log.Printf("%s/%s", info.GetDomain(), info.GetReason())
+//	}
+//
+// Unlike the details of ToStatus, repeated details are not merged.
+func DetailsOf[T proto.Message](err error) []T {
+	var found []T
+	Walk(err, func(msg proto.Message) bool {
+		if v, ok := msg.(T); ok {
+			found = append(found, v)
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,80 @@
+package errdetails
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestDetailsOf(t *testing.T) {
+	testHandler(t)
+
+	err := New(codes.FailedPrecondition, "wallet is frozen",
+		Cause(&errdetails.ErrorInfo{Reason: "WALLET_FROZEN", Domain: "wallet.platform.test"}),
+		BadRequest(&errdetails.BadRequest_FieldViolation{Field: "from"}),
+	)
+	err = Join(err, WithCause(errors.New("ledger unavailable"), &errdetails.ErrorInfo{
+		Reason: "LEDGER_UNAVAILABLE",
+		Domain: "ledger.platform.test",
+	}))
+	err = fmt.Errorf("transfer: %w", WithBadRequest(err, &errdetails.BadRequest_FieldViolation{Field: "amount"}))
+
+	infos := DetailsOf[*errdetails.ErrorInfo](err)
+	require.Len(t, infos, 2)
+	require.Equal(t, "WALLET_FROZEN", infos[0].GetReason())
+	require.Equal(t, "LEDGER_UNAVAILABLE", infos[1].GetReason())
+
+	reqs := DetailsOf[*errdetails.BadRequest](err)
+	require.Len(t, reqs, 2)
+	require.Equal(t, "amount", reqs[0].GetFieldViolations()[0].GetField())
+	require.Equal(t, "from", reqs[1].GetFieldViolations()[0].GetField())
+
+	require.Empty(t, DetailsOf[*errdetails.RetryInfo](err))
+	require.Empty(t, DetailsOf[*errdetails.ErrorInfo](nil))
+}
+
+func TestDetailsOfArbitrary(t *testing.T) {
+	testHandler(t)
+
+	detail, err := anypb.New(structpb.NewStringValue("wallet"))
+	require.NoError(t, err)
+
+	err = FromStatus(status.FromProto(&statuspb.Status{Code: int32(codes.Internal), Message: "oops", Details: []*anypb.Any{detail}}))
+
+	values := DetailsOf[*structpb.Value](err)
+	require.Len(t, values, 1)
+	require.Equal(t, "wallet", values[0].GetStringValue())
+}
+
+func TestWalk(t *testing.T) {
+	testHandler(t)
+
+	err := New(codes.Unavailable, "ledger unavailable",
+		RetryDelay(0),
+		Stack(),
+		Help(&errdetails.Help_Link{Url: "https://ledger.platform.test"}),
+	)
+
+	var names []string
+	Walk(err, func(msg proto.Message) bool {
+		names = append(names, string(msg.ProtoReflect().Descriptor().Name()))
+		return true
+	})
+	require.Equal(t, []string{"Help", "DebugInfo", "RetryInfo"}, names)
+
+	names = nil
+	Walk(err, func(msg proto.Message) bool {
+		names = append(names, string(msg.ProtoReflect().Descriptor().Name()))
+		return false
+	})
+	require.Equal(t, []string{"Help"}, names)
+}
@@ -172,6 +172,19 @@ func WithLocalizedMessage(err error, msg details.LocalizedMessage) LocalizedErro
 	return &localizedError{error: err, LocalizedMessage: details}
 }
 
+// Localized provides a Details wrapper to enrich errors with LocalizableError details.
+func Localized(id string, args map[string]string) Details {
+	return wrapperFunc(func(err error) error {
+		return WithLocalized(err, id, args)
+	})
+}
+
+// WithLocalized wraps an error with a message from the package level Catalog,
+// rendered once the client's locale is known.
+func WithLocalized(err error, id string, args map[string]string) LocalizableError {
+	return &errLocalizable{error: err, id: id, args: args}
+}
+
 // PreconditionFailure provides a Details wrapper to enrich errors with FailedPreconditionError details.
 func PreconditionFailure(violations ...details.PreconditionViolation) Details {
 	return wrapperFunc(func(err error) error {